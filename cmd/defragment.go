@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/samjwillis97/GoTDMS/pkg/cli"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(defragmentCmd)
+}
+
+var defragmentCmd = &cobra.Command{
+	Use:   "defragment [file] [output]",
+	Short: "Rewrites a TDMS File as one segment per group, and its index",
+	Long: `Rewrites a TDMS File as one segment per group, and its index
+
+Each group's channels are written contiguously with their latest
+properties, so files logged a few samples at a time read quickly.
+The output defaults to the file name with "_defragmented" added.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		filePath := args[0]
+		outPath := strings.TrimSuffix(filePath, filepath.Ext(filePath)) + "_defragmented" + filepath.Ext(filePath)
+		if len(args) == 2 {
+			outPath = args[1]
+		}
+		if _, err := os.Stat(filePath); os.IsNotExist(err) {
+			return err
+		}
+		if filepath.Clean(outPath) == filepath.Clean(filePath) {
+			return fmt.Errorf("output %s would overwrite the file being defragmented", outPath)
+		}
+		return cli.DefragmentFile(filePath, outPath)
+	},
+}
@@ -20,12 +20,11 @@ var listCmd = &cobra.Command{
 		if _, err := os.Stat(filePath); os.IsNotExist(err) {
 			return err
 		}
-		file, err := os.OpenFile(filePath, os.O_RDONLY, 0666)
+		file, err := openTDMSFile(filePath)
 		if err != nil {
 			return err
 		}
-		cli.DisplayFile(file, Verbose)
-		file.Close()
-		return nil
+		defer file.Close()
+		return cli.DisplayFile(file, Verbose)
 	},
 }
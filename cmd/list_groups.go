@@ -21,12 +21,11 @@ var listGroupsCmd = &cobra.Command{
 		if _, err := os.Stat(filePath); os.IsNotExist(err) {
 			return err
 		}
-		file, err := os.OpenFile(filePath, os.O_RDONLY, 0666)
+		file, err := openTDMSFile(filePath)
 		if err != nil {
 			return err
 		}
-		cli.DisplayGroups(file)
-		file.Close()
-		return nil
+		defer file.Close()
+		return cli.DisplayGroups(file)
 	},
 }
@@ -22,12 +22,11 @@ var listPropertiesCmd = &cobra.Command{
 		if _, err := os.Stat(filePath); os.IsNotExist(err) {
 			return err
 		}
-		file, err := os.OpenFile(filePath, os.O_RDONLY, 0666)
+		file, err := openTDMSFile(filePath)
 		if err != nil {
 			return err
 		}
-		cli.DisplayChannelProperties(file, groupName, channelName)
-		file.Close()
-		return nil
+		defer file.Close()
+		return cli.DisplayChannelProperties(file, groupName, channelName)
 	},
 }
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"os"
+	"strconv"
+
+	"github.com/samjwillis97/GoTDMS/pkg/cli"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(readChannelRangeCmd)
+}
+
+var readChannelRangeCmd = &cobra.Command{
+	Use:   "read-channel-range [file] [group] [channel] [start] [count]",
+	Short: "Outputs a range of samples from the chosen channel",
+	Args:  cobra.ExactArgs(5),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		filePath := args[0]
+		groupName := args[1]
+		chanName := args[2]
+		start, err := strconv.ParseUint(args[3], 10, 64)
+		if err != nil {
+			return err
+		}
+		count, err := strconv.ParseUint(args[4], 10, 64)
+		if err != nil {
+			return err
+		}
+		if _, err := os.Stat(filePath); os.IsNotExist(err) {
+			return err
+		}
+		file, err := openTDMSFile(filePath)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		return cli.DisplayChannelRange(file, groupName, chanName, start, count)
+	},
+}
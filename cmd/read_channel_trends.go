@@ -22,12 +22,11 @@ var readChannelTrendsCmd = &cobra.Command{
 		if _, err := os.Stat(filePath); os.IsNotExist(err) {
 			return err
 		}
-		file, err := os.OpenFile(filePath, os.O_RDONLY, 0666)
+		file, err := openTDMSFile(filePath)
 		if err != nil {
 			return err
 		}
-		cli.DisplayChannelData(file, groupName, chanName)
-		file.Close()
-		return nil
+		defer file.Close()
+		return cli.DisplayChannelData(file, groupName, chanName)
 	},
 }
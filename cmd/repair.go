@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/samjwillis97/GoTDMS/pkg/cli"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(repairCmd)
+}
+
+var repairCmd = &cobra.Command{
+	Use:   "repair [file] [output]",
+	Short: "Writes a repaired copy of a damaged TDMS File, and its index",
+	Long: `Writes a repaired copy of a damaged TDMS File, and its index
+
+Segments whose metadata can not be read are dropped, and a truncated
+final segment keeps its whole chunks and the values written of its
+partial final chunk. The output defaults to the file name with
+"_repaired" added.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		filePath := args[0]
+		outPath := strings.TrimSuffix(filePath, filepath.Ext(filePath)) + "_repaired" + filepath.Ext(filePath)
+		if len(args) == 2 {
+			outPath = args[1]
+		}
+		if _, err := os.Stat(filePath); os.IsNotExist(err) {
+			return err
+		}
+		if filepath.Clean(outPath) == filepath.Clean(filePath) {
+			return fmt.Errorf("output %s would overwrite the file being repaired", outPath)
+		}
+		return cli.RepairFile(filePath, outPath)
+	},
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"time"
 
+	"github.com/samjwillis97/GoTDMS/pkg/tdms"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
@@ -15,12 +16,15 @@ var (
 	Json    bool
 	Debug   bool
 	Timed   bool
+	Mmap    bool
 
 	StartTime time.Time
 
 	rootCmd = &cobra.Command{
 		Use:   "gotdms",
 		Short: "GoTDMS is a Command Line NI TDMS File Reader",
+		// Errors from reading a file are not usage errors
+		SilenceUsage: true,
 		PersistentPostRun: func(cmd *cobra.Command, args []string) {
 			if Timed {
 				elapsed := time.Since(StartTime)
@@ -42,6 +46,15 @@ func init() {
 	rootCmd.PersistentFlags().BoolVarP(&Json, "json", "j", false, "json formatted output")
 	rootCmd.PersistentFlags().BoolVarP(&Debug, "debug", "d", false, "debug mode")
 	rootCmd.PersistentFlags().BoolVarP(&Timed, "timed", "t", false, "use for timing")
+	rootCmd.PersistentFlags().BoolVarP(&Mmap, "mmap", "m", false, "memory map the file, where supported")
+}
+
+// Opens a TDMS File, memory mapped if requested
+func openTDMSFile(filePath string) (*tdms.File, error) {
+	if Mmap {
+		return tdms.OpenMmap(filePath)
+	}
+	return tdms.Open(filePath)
 }
 
 func initFunction() {
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"os"
+	"time"
+
+	"github.com/samjwillis97/GoTDMS/pkg/cli"
+	"github.com/spf13/cobra"
+)
+
+var (
+	TailInterval  time.Duration
+	TailSamples   bool
+	TailFromStart bool
+)
+
+func init() {
+	tailCmd.Flags().DurationVarP(&TailInterval, "interval", "i", time.Second, "time between checks for new data")
+	tailCmd.Flags().BoolVarP(&TailSamples, "samples", "s", false, "output every new sample instead of trends")
+	tailCmd.Flags().BoolVarP(&TailFromStart, "from-start", "f", false, "also output the data already in the file")
+	rootCmd.AddCommand(tailCmd)
+}
+
+var tailCmd = &cobra.Command{
+	Use:   "tail [file] [group] [channel]",
+	Short: "Follows the chosen channel as data is appended to the file",
+	Args:  cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		filePath := args[0]
+		groupName := args[1]
+		chanName := args[2]
+		if _, err := os.Stat(filePath); os.IsNotExist(err) {
+			return err
+		}
+		return cli.DisplayTail(filePath, groupName, chanName, TailInterval, TailSamples, TailFromStart)
+	},
+}
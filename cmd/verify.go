@@ -0,0 +1,25 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/samjwillis97/GoTDMS/pkg/cli"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(verifyCmd)
+}
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify [file]",
+	Short: "Checks every segment of a TDMS File, listing any problems found",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		filePath := args[0]
+		if _, err := os.Stat(filePath); os.IsNotExist(err) {
+			return err
+		}
+		return cli.VerifyFile(filePath)
+	},
+}
@@ -1,9 +1,14 @@
 package main
 
 import (
+	"os"
+
 	"github.com/samjwillis97/GoTDMS/cmd"
 )
 
 func main() {
-	cmd.Execute()
+	// Cobra has already printed the error
+	if err := cmd.Execute(); err != nil {
+		os.Exit(1)
+	}
 }
@@ -0,0 +1,27 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/samjwillis97/GoTDMS/pkg/tdms"
+)
+
+func DefragmentFile(filePath string, outPath string) error {
+	err := tdms.DefragmentFile(filePath, outPath)
+	if err != nil {
+		return err
+	}
+
+	before, err := os.Stat(filePath)
+	if err != nil {
+		return err
+	}
+	after, err := os.Stat(outPath)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Defragmented File:\t%s\n", outPath)
+	fmt.Printf("Size:\t%d bytes, from %d bytes\n", after.Size(), before.Size())
+	return nil
+}
@@ -0,0 +1,16 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/samjwillis97/GoTDMS/pkg/tdms"
+)
+
+func WriteIndexFile(filePath string) error {
+	indexPath, err := tdms.WriteIndexFile(filePath)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Index written to %s\n", indexPath)
+	return nil
+}
@@ -11,35 +11,23 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
-func DisplayFile(file *os.File, verbose bool) {
-	// Get All Segments, Find all Non Duplicates
-	// Get Each Group, Each Channel and All Properties
-	segments, _ := tdms.ReadAllSegments(file)
-
-	paths := tdms.ReadAllUniqueTDMSObjects(segments)
-
-	groups := tdms.GetGroupsFromPathArray(paths)
+func DisplayFile(file *tdms.File, verbose bool) error {
+	groups := file.Groups()
 
 	writer := tabwriter.NewWriter(os.Stdout, 0, 8, 1, '\t', tabwriter.AlignRight)
 
 	for groupIter, group := range groups {
 		// GROUPS
-		formattedG := strings.Replace(group, "/", "", -1)
-		formattedG = strings.Replace(formattedG, "'", "", -1)
-
 		if groupIter == len(groups)-1 {
-			fmt.Fprintf(writer, "└── %s\n", formattedG)
+			fmt.Fprintf(writer, "└── %s\n", group.Name())
 		} else {
-			fmt.Fprintf(writer, "├── %s\n", formattedG)
+			fmt.Fprintf(writer, "├── %s\n", group.Name())
 		}
 
-		channels := tdms.GetChannelsFromPathArray(paths, formattedG)
+		channels := group.Channels()
 
 		for chanIter, channel := range channels {
 			// CHANNELS
-			formattedC := strings.Replace(channel, "/", "", -1)
-			formattedC = strings.Replace(formattedC, "'", "", -1)
-
 			var chFormatString strings.Builder
 
 			if groupIter != len(groups)-1 {
@@ -53,21 +41,10 @@ func DisplayFile(file *os.File, verbose bool) {
 			}
 			chFormatString.WriteString(" %s\n")
 
-			fmt.Fprintf(writer, chFormatString.String(), formattedC)
-
-			var properties tdms.Properties
-			for _, val := range segments {
-				//PROPERTIES
-				for path, propMap := range val.PropMap {
-					if path == ("/'" + formattedG + "'/'" + formattedC + "'") {
-						for _, propValue := range propMap {
-							properties = append(properties, propValue)
-						}
-					}
-				}
-			}
+			fmt.Fprintf(writer, chFormatString.String(), channel.Name())
 
-			sort.Sort(properties)
+			//PROPERTIES
+			properties := sortedProperties(channel.Properties())
 			if verbose {
 				for propIter, val := range properties {
 
@@ -93,158 +70,74 @@ func DisplayFile(file *os.File, verbose bool) {
 			}
 		}
 	}
-	writer.Flush()
+	return writer.Flush()
 }
 
-func DisplayGroups(file *os.File) {
-	segments, _ := tdms.ReadAllSegments(file)
-
-	paths := tdms.ReadAllUniqueTDMSObjects(segments)
-
-	groups := tdms.GetGroupsFromPathArray(paths)
-
-	for _, group := range groups {
-		formatted := strings.Replace(group, "/", "", -1)
-		formatted = strings.Replace(formatted, "'", "", -1)
-		fmt.Println(formatted)
+func DisplayGroups(file *tdms.File) error {
+	for _, group := range file.Groups() {
+		fmt.Println(group.Name())
 	}
+	return nil
 }
 
-func DisplayGroupChannels(file *os.File, groupName string) {
-	segments, _ := tdms.ReadAllSegments(file)
-
-	paths := tdms.ReadAllUniqueTDMSObjects(segments)
-
-	groups := tdms.GetGroupsFromPathArray(paths)
-
-	groupPresent := false
-	for _, group := range groups {
-		formatted := strings.Replace(group, "/", "", -1)
-		formatted = strings.Replace(formatted, "'", "", -1)
-		if formatted == groupName {
-			groupPresent = true
-			log.Debugf("Found matching Group")
-		}
-	}
-
-	var channels []string
-
-	if groupPresent {
-		channels = tdms.GetChannelsFromPathArray(paths, groupName)
-	} else {
-		fmt.Println("File does not contain group named: ", groupName)
-		log.Fatal("Invalid Group Name")
+func DisplayGroupChannels(file *tdms.File, groupName string) error {
+	group, err := file.Group(groupName)
+	if err != nil {
+		return err
 	}
+	log.Debugf("Found matching Group")
 
-	for _, channel := range channels {
-		formatted := strings.Replace(channel, "/", "", -1)
-		formatted = strings.Replace(formatted, "'", "", -1)
-		fmt.Println(formatted)
+	for _, channel := range group.Channels() {
+		fmt.Println(channel.Name())
 	}
+	return nil
 }
 
-func DisplayChannelProperties(file *os.File, groupName string, channelName string) {
-	segments, _ := tdms.ReadAllSegments(file)
-
-	paths := tdms.ReadAllUniqueTDMSObjects(segments)
-
-	groups := tdms.GetGroupsFromPathArray(paths)
-
-	groupPresent := false
-	for _, group := range groups {
-		formatted := strings.Replace(group, "/", "", -1)
-		formatted = strings.Replace(formatted, "'", "", -1)
-		if formatted == groupName {
-			groupPresent = true
-			log.Debugf("Found matching Group")
-		}
-	}
-
-	var channels []string
-
-	if groupPresent {
-		channels = tdms.GetChannelsFromPathArray(paths, groupName)
-	} else {
-		fmt.Println("File does not contain group named: ", groupName)
-		log.Fatal("Invalid Group Name")
-	}
-
-	channelPresent := false
-	for _, channel := range channels {
-		formatted := strings.Replace(channel, "/", "", -1)
-		formatted = strings.Replace(formatted, "'", "", -1)
-		if formatted == channelName {
-			channelPresent = true
-			log.Debugf("Found matching channel")
-		}
-	}
-
-	var properties tdms.Properties
-	// Get All Property Maps from segmetns containing a match, keep overriding to keep last
-	if channelPresent {
-		for _, val := range segments {
-			for path, propMap := range val.PropMap {
-				if path == ("/'" + groupName + "'/'" + channelName + "'") {
-					for _, propValue := range propMap {
-						properties = append(properties, propValue)
-					}
-				}
-			}
-		}
+func DisplayChannelProperties(file *tdms.File, groupName string, channelName string) error {
+	channel, err := findChannel(file, groupName, channelName)
+	if err != nil {
+		return err
 	}
 
-	sort.Sort(properties)
-
 	writer := tabwriter.NewWriter(os.Stdout, 0, 8, 1, '\t', tabwriter.AlignRight)
-	for _, val := range properties {
+	for _, val := range sortedProperties(channel.Properties()) {
 		fmt.Fprintf(writer, "%s\t%s\n", val.Name, val.StringValue)
 	}
-	writer.Flush()
+	return writer.Flush()
 }
 
-func DisplayChannelData(file *os.File, groupName string, channelName string) {
-	segments, props := tdms.ReadAllSegments(file)
-
-	paths := tdms.ReadAllUniqueTDMSObjects(segments)
-
-	groups := tdms.GetGroupsFromPathArray(paths)
-
-	groupPresent := false
-	var groupString string
-	for _, group := range groups {
-		formatted := strings.Replace(group, "/", "", -1)
-		formatted = strings.Replace(formatted, "'", "", -1)
-		if formatted == groupName {
-			groupPresent = true
-			groupString = group
-			log.Debugf("Found matching Group")
-		}
+func DisplayChannelData(file *tdms.File, groupName string, channelName string) error {
+	channel, err := findChannel(file, groupName, channelName)
+	if err != nil {
+		return err
 	}
 
-	var channels []string
-	var channelString string
+	return DisplayChannelRawData(file, channel, -1, 0)
+}
 
-	if groupPresent {
-		channels = tdms.GetChannelsFromPathArray(paths, groupName)
-	} else {
-		fmt.Println("File does not contain group named: ", groupName)
-		log.Fatalln("Invalid Group Name")
+// Finds a Channel by its Group and Channel names
+func findChannel(file *tdms.File, groupName string, channelName string) (*tdms.Channel, error) {
+	group, err := file.Group(groupName)
+	if err != nil {
+		return nil, err
 	}
+	log.Debugf("Found matching Group")
 
-	channelPresent := false
-	for _, channel := range channels {
-		formatted := strings.Replace(channel, "/", "", -1)
-		formatted = strings.Replace(formatted, "'", "", -1)
-		if formatted == channelName {
-			channelPresent = true
-			channelString = channel
-			log.Debugf("Found matching channel")
-		}
+	channel, err := group.Channel(channelName)
+	if err != nil {
+		return nil, err
 	}
+	log.Debugf("Found matching channel")
 
-	if channelPresent {
-		fullPath := groupString + "/" + channelString
-		DisplayChannelRawData(file, fullPath, -1, 0, segments, props)
-	}
+	return channel, nil
 }
 
+// Properties sorted by name
+func sortedProperties(propMap map[string]tdms.Property) tdms.Properties {
+	var properties tdms.Properties
+	for _, propValue := range propMap {
+		properties = append(properties, propValue)
+	}
+	sort.Sort(properties)
+	return properties
+}
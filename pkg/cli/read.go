@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math"
 	"os"
+	"reflect"
 	"text/tabwriter"
 
 	"github.com/samjwillis97/GoTDMS/pkg/analysis"
@@ -11,12 +12,17 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
-func DisplayChannelRawData(file *os.File, channelPath string, length int64, offset uint64, allSegments []tdms.Segment, allProps map[string]map[string]tdms.Property) {
+func DisplayChannelRawData(file *tdms.File, channel *tdms.Channel, length int64, offset uint64) error {
 	// Determine Data Type of Segment
 	// if TWF, defined by the properties
 	// return RMS, P-P, CF for the whole file, add option for Block-by-block, that returns a slice
 	firstSeg := true
 
+	src := file.Source()
+	allSegments := file.Segments()
+	channelPath := channel.Path()
+	props := channel.Properties()
+
 	writer := tabwriter.NewWriter(os.Stdout, 0, 8, 1, '\t', tabwriter.AlignRight)
 
 	// Iterate through all File Segments
@@ -26,22 +32,31 @@ func DisplayChannelRawData(file *os.File, channelPath string, length int64, offs
 		for _, objPath := range segment.ObjectOrder {
 			obj := segment.Objects[objPath]
 
-			if objPath == channelPath {
+			if objPath == channelPath && obj.HasRawData() {
 
-				_, wfStartPresent := allProps[objPath]["wf_start_time"]
-				_, wfStartOffsetPresent := allProps[objPath]["wf_start_offset"]
-				_, wfIncrementPresent := allProps[objPath]["wf_increment"]
-				_, wfSamplesPresent := allProps[objPath]["wf_samples"]
+				_, wfStartPresent := props["wf_start_time"]
+				_, wfStartOffsetPresent := props["wf_start_offset"]
+				_, wfIncrementPresent := props["wf_increment"]
+				_, wfSamplesPresent := props["wf_samples"]
 
 				// fmt.Println("New Segment:", i)
-				// fmt.Println(allProps[objPath])
+				// fmt.Println(props)
 
 				if wfStartPresent && wfStartOffsetPresent && wfIncrementPresent && wfSamplesPresent {
 					log.Debugln("Waveform Present")
 
-					wf_increment := tdms.ReadDBL(file, allProps[objPath]["wf_increment"].ValuePosition, 0)
-					wf_samples := tdms.ReadInt32(file, allProps[objPath]["wf_samples"].ValuePosition, 0)
-					wf_start_time := tdms.ReadTime(file, allProps[objPath]["wf_start_time"].ValuePosition, 0)
+					wf_increment, err := props["wf_increment"].AsFloat64()
+					if err != nil {
+						return err
+					}
+					wf_samples, err := props["wf_samples"].AsInt64()
+					if err != nil {
+						return err
+					}
+					wf_start_time, err := props["wf_start_time"].AsTime()
+					if err != nil {
+						return err
+					}
 
 					if firstSeg {
 						fmt.Printf("TDMS Path:\t%s\n", channelPath)
@@ -53,27 +68,29 @@ func DisplayChannelRawData(file *os.File, channelPath string, length int64, offs
 						fmt.Fprintf(writer, "\nSeg No. \tRMS \tP-P \tCF\n")
 					}
 
-					_, err := file.Seek(int64(segment.DataPos), 0)
-					if err != nil {
-						log.Fatalln("Error from file.Seek in readChannelRawData")
-					}
-
-					data := make([]float64, 0)
-
-					switch obj.RawDataIndex.DataType {
-					case tdms.SGL:
-						dataSGL := tdms.ReadSGLArray(file, int64(obj.RawDataIndex.NumValues), int64(obj.RawDataIndex.RawDataSize), 1)
-
-						//convert data to float64
-						for _, val := range dataSGL {
-							data = append(data, float64(val))
+					var data []float64
+					if obj.RawDataIndex.DAQmx != nil {
+						scaled, err := tdms.ReadSegmentDAQmxData(src, segment, objPath, props)
+						if err != nil {
+							return err
+						}
+						switch values := scaled.(type) {
+						case []float64:
+							data = values
+						case []bool:
+							// Digital Lines trend as 0 or 1
+							data = make([]float64, len(values))
+							for j, v := range values {
+								if v {
+									data[j] = 1
+								}
+							}
+						}
+					} else {
+						data, err = tdms.ReadSegmentChannelFloat64(src, segment, objPath)
+						if err != nil {
+							return err
 						}
-
-					case tdms.DBL:
-						data = tdms.ReadDBLArray(file, int64(obj.RawDataIndex.NumValues), int64(obj.RawDataIndex.RawDataSize), 1)
-
-					default:
-						log.Fatal("Data Type Not Implemented")
 					}
 
 					rms := analysis.RmsFloat64Slice(data)
@@ -93,5 +110,25 @@ func DisplayChannelRawData(file *os.File, channelPath string, length int64, offs
 			}
 		}
 	}
-	writer.Flush()
+	return writer.Flush()
+}
+
+func DisplayChannelRange(file *tdms.File, groupName string, channelName string, start uint64, count uint64) error {
+	channel, err := findChannel(file, groupName, channelName)
+	if err != nil {
+		return err
+	}
+
+	data, err := channel.ReadRange(start, count)
+	if err != nil {
+		return err
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 8, 1, '\t', tabwriter.AlignRight)
+	fmt.Fprintf(writer, "Index \tValue\n")
+	values := reflect.ValueOf(data)
+	for i := 0; i < values.Len(); i++ {
+		fmt.Fprintf(writer, "%d \t%v\n", start+uint64(i), values.Index(i).Interface())
+	}
+	return writer.Flush()
 }
@@ -0,0 +1,37 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/samjwillis97/GoTDMS/pkg/tdms"
+)
+
+// Writes a repaired copy of a TDMS File, listing the problems found and
+// what was salvaged
+func RepairFile(filePath string, outPath string) error {
+	result, err := tdms.RepairFile(filePath, outPath)
+	if err != nil {
+		return err
+	}
+
+	if len(result.Issues) > 0 {
+		writer := tabwriter.NewWriter(os.Stdout, 0, 8, 1, '\t', tabwriter.AlignRight)
+		fmt.Fprintf(writer, "Seg No. \tOffset \tIssue\n")
+		for _, issue := range result.Issues {
+			fmt.Fprintf(writer, "%d \t%d \t%s\n", issue.Segment, issue.Offset, describeIssue(issue.Err))
+		}
+		err = writer.Flush()
+		if err != nil {
+			return err
+		}
+		fmt.Println()
+	}
+
+	fmt.Printf("Segments Kept:\t%d\n", result.Segments)
+	fmt.Printf("Segments Dropped:\t%d %v\n", len(result.Dropped), result.Dropped)
+	fmt.Printf("Bytes Truncated:\t%d\n", result.TruncatedBytes)
+	fmt.Printf("Repaired File:\t%s (%d bytes)\n", outPath, result.Size)
+	return nil
+}
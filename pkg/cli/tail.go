@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"reflect"
+	"text/tabwriter"
+	"time"
+
+	"github.com/samjwillis97/GoTDMS/pkg/analysis"
+	"github.com/samjwillis97/GoTDMS/pkg/tdms"
+)
+
+// Follows a Channel of a TDMS File while it is being written
+// Prints the trends of each new Segment, or every new sample
+// The data already in the File is skipped unless fromStart is set
+func DisplayTail(filePath string, groupName string, channelName string, interval time.Duration, samples bool, fromStart bool) error {
+	watcher, err := tdms.Watch(filePath)
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	channelPath := tdms.ChannelPath(groupName, channelName)
+	writer := tabwriter.NewWriter(os.Stdout, 0, 8, 1, '\t', tabwriter.AlignRight)
+	if samples {
+		fmt.Fprintf(writer, "Index \tValue\n")
+	} else {
+		fmt.Fprintf(writer, "Seg No. \tSamples \tRMS \tP-P \tCF\n")
+	}
+
+	index := uint64(0)
+	for first := true; ; first = false {
+		updates, err := watcher.Poll()
+		if err != nil {
+			return err
+		}
+
+		for _, update := range updates {
+			if samples {
+				// Values already in the File are counted, not read
+				if first && !fromStart {
+					n, err := watcher.UpdateNumValues(channelPath, update)
+					if err != nil {
+						return err
+					}
+					index += n
+					continue
+				}
+				data, err := watcher.ReadUpdate(channelPath, update)
+				if err != nil {
+					return err
+				}
+				if data == nil {
+					continue
+				}
+				values := reflect.ValueOf(data)
+				for i := 0; i < values.Len(); i++ {
+					fmt.Fprintf(writer, "%d \t%v\n", index, values.Index(i).Interface())
+					index++
+				}
+				continue
+			}
+
+			if first && !fromStart {
+				continue
+			}
+			data, err := watcher.ReadUpdateFloat64(channelPath, update)
+			if err != nil {
+				return err
+			}
+			if len(data) == 0 {
+				continue
+			}
+			rms := analysis.RmsFloat64Slice(data)
+			min, max := analysis.MinMaxFloat64Slice(data)
+			pp := math.Abs(max - min)
+			cf := max / rms
+			fmt.Fprintf(writer, "%d \t%d \t%.4f \t%.4f \t%.4f\n", update.Segment, len(data), rms, pp, cf)
+		}
+
+		err = writer.Flush()
+		if err != nil {
+			return err
+		}
+		time.Sleep(interval)
+	}
+}
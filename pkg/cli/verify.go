@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/samjwillis97/GoTDMS/pkg/tdms"
+)
+
+// Lists the problems found in a TDMS File
+// Returns an error if there are any, so the command exits non-zero
+func VerifyFile(filePath string) error {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	src, err := tdms.NewFileSource(file)
+	if err != nil {
+		return err
+	}
+
+	issues := tdms.Verify(src)
+	if len(issues) == 0 {
+		fmt.Println("No issues found")
+		return nil
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 8, 1, '\t', tabwriter.AlignRight)
+	fmt.Fprintf(writer, "Seg No. \tOffset \tIssue\n")
+	for _, issue := range issues {
+		fmt.Fprintf(writer, "%d \t%d \t%s\n", issue.Segment, issue.Offset, describeIssue(issue.Err))
+	}
+	err = writer.Flush()
+	if err != nil {
+		return err
+	}
+	return fmt.Errorf("%d issues found in %s", len(issues), filePath)
+}
+
+// Describes an Issue without the Byte Offset, which is listed separately
+func describeIssue(err error) string {
+	var tdmsErr *tdms.Error
+	if !errors.As(err, &tdmsErr) {
+		return err.Error()
+	}
+	if tdmsErr.Path != "" {
+		return fmt.Sprintf("%s (%s): %v", tdmsErr.Op, tdmsErr.Path, tdmsErr.Err)
+	}
+	return fmt.Sprintf("%s: %v", tdmsErr.Op, tdmsErr.Err)
+}
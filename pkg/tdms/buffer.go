@@ -0,0 +1,139 @@
+package tdms
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// Bytes read after a Segment's Lead In, so the Metadata of most
+// Segments is read along with the Lead In
+const segmentReadAhead = 4096
+
+// Size in Bytes of a Segment's Lead In
+const leadInSize = 28
+
+// Segment Length of a Segment that is still being written, or was
+// never finished
+const incompleteSegLength = 0xFFFFFFFFFFFFFFFF
+
+// Tags that start a Segment's Lead In
+const (
+	segmentTag = "TDSm"
+	indexTag   = "TDSh"
+)
+
+// An io.ReadSeeker over bytes read from a Source in a single read
+//
+// Positions are those in the Source, so the offsets in errors and
+// Property ValuePositions are the same as reading the Source directly
+// Reading outside of the buffered bytes returns io.EOF
+type bufferedReader struct {
+	buf  []byte
+	base int64
+	pos  int64
+	size int64
+}
+
+func (b *bufferedReader) Read(p []byte) (int, error) {
+	if b.pos < b.base || b.pos >= b.base+int64(len(b.buf)) {
+		return 0, io.EOF
+	}
+	n := copy(p, b.buf[b.pos-b.base:])
+	b.pos += int64(n)
+	return n, nil
+}
+
+func (b *bufferedReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += b.pos
+	case io.SeekEnd:
+		offset += b.size
+	default:
+		return 0, errors.New("tdms: invalid whence")
+	}
+	if offset < 0 {
+		return 0, errors.New("tdms: negative position")
+	}
+	b.pos = offset
+	return offset, nil
+}
+
+// Reads the Lead In and Metadata of the Segment at pos
+// Usually a single read, a second is made if the Metadata is larger
+// than segmentReadAhead
+// Sources held in memory are parsed in place, without a read
+//
+// buf is reused if large enough, values parsed from the reader are
+// copied out of it, so it can be reused for the next Segment
+//
+// Returns a reader positioned at the start of the Segment,
+// and the buffer to reuse for the next Segment
+func readSegmentBuffer(src Source, pos int64, buf []byte) (*bufferedReader, []byte, error) {
+	if mem, ok := src.(memorySource); ok {
+		return &bufferedReader{mem.Bytes()[pos:], pos, pos, src.Size()}, buf, nil
+	}
+
+	remaining := src.Size() - pos
+	n := int64(leadInSize + segmentReadAhead)
+	if n > remaining {
+		n = remaining
+	}
+	if n <= 0 {
+		return nil, buf, newError("read lead-in", pos, ErrTruncatedSegment)
+	}
+
+	buf = growBuffer(buf, n)
+	err := readAt(src, buf, pos, "read lead-in")
+	if err != nil {
+		return nil, buf, err
+	}
+
+	// Too short for a Lead In, though the tag is checked first so
+	// something other than TDMS is reported as such
+	if n < leadInSize {
+		if n >= 4 {
+			if tag := string(buf[0:4]); tag != segmentTag && tag != indexTag {
+				return nil, buf, newError("read lead-in", pos, ErrNotTDMS)
+			}
+		}
+		return nil, buf, newError("read lead-in", pos, ErrTruncatedSegment)
+	}
+
+	// Metadata Length is the last 8 bytes of the Lead In
+	// An invalid Lead In is left for ReadLeadIn to report, and Metadata
+	// longer than its Segment for readSegment
+	if tag := string(buf[0:4]); tag == segmentTag || tag == indexTag {
+		order := byteOrder(binary.LittleEndian.Uint32(buf[4:8]))
+		segLength := order.Uint64(buf[12:20])
+		metaLength := order.Uint64(buf[20:28])
+		if segLength != incompleteSegLength && metaLength > segLength {
+			metaLength = segLength
+		}
+		want := uint64(remaining)
+		if metaLength < want-leadInSize {
+			want = leadInSize + metaLength
+		}
+		if want > uint64(n) {
+			buf = growBuffer(buf, int64(want))
+			err := readAt(src, buf[n:], pos+n, "read metadata")
+			if err != nil {
+				return nil, buf, err
+			}
+		}
+	}
+
+	return &bufferedReader{buf, pos, pos, src.Size()}, buf, nil
+}
+
+// Returns a buffer of length n, reusing buf if it is large enough
+func growBuffer(buf []byte, n int64) []byte {
+	if int64(cap(buf)) >= n {
+		return buf[:n]
+	}
+	grown := make([]byte, n)
+	copy(grown, buf)
+	return grown
+}
@@ -0,0 +1,317 @@
+package tdms
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Raw Data Index Headers of DAQmx Objects
+const (
+	daqmxFormatChangingScaler uint32 = 0x1269
+	daqmxDigitalLineScaler    uint32 = 0x126A
+)
+
+// Data Type of values in a DAQmx Raw Buffer
+type DAQmxDataType uint32
+
+const (
+	DAQmxUint8     DAQmxDataType = 0
+	DAQmxInt8      DAQmxDataType = 1
+	DAQmxUint16    DAQmxDataType = 2
+	DAQmxInt16     DAQmxDataType = 3
+	DAQmxUint32    DAQmxDataType = 4
+	DAQmxInt32     DAQmxDataType = 5
+	DAQmxUint64    DAQmxDataType = 6
+	DAQmxInt64     DAQmxDataType = 7
+	DAQmxSGL       DAQmxDataType = 8
+	DAQmxDBL       DAQmxDataType = 9
+	DAQmxTimestamp DAQmxDataType = 0xFFFFFFFF
+)
+
+// Size in Bytes of a single value of the DAQmx Data Type
+// Returns 0 if the Data Type is unknown
+func (t DAQmxDataType) Size() int {
+	switch t {
+	case DAQmxUint8, DAQmxInt8:
+		return 1
+	case DAQmxUint16, DAQmxInt16:
+		return 2
+	case DAQmxUint32, DAQmxInt32, DAQmxSGL:
+		return 4
+	case DAQmxUint64, DAQmxInt64, DAQmxDBL:
+		return 8
+	case DAQmxTimestamp:
+		return 16
+	}
+	return 0
+}
+
+// Information from a DAQmx Raw Data Index
+type DAQmxIndex struct {
+	// True for Digital Line Scalers, where the offset is in bits
+	DigitalLine bool
+	Scalers     []DAQmxScaler
+	// Width in Bytes of one sample of each Raw Buffer
+	RawDataWidths []uint32
+}
+
+// Describes where a Channel's values are in the DAQmx Raw Buffers
+type DAQmxScaler struct {
+	DataType       DAQmxDataType
+	RawBufferIndex uint32
+	// Byte Offset within a Raw Buffer sample
+	// Bit Offset for Digital Line Scalers
+	RawOffset    uint32
+	SampleFormat uint32
+	ScaleID      uint32
+}
+
+// Bytes of all Raw Buffers for a single value of each Channel
+func (d DAQmxIndex) ChunkWidth() uint64 {
+	width := uint64(0)
+	for _, w := range d.RawDataWidths {
+		width += uint64(w)
+	}
+	return width
+}
+
+// Reads the DAQmx part of a Raw Data Index
+// Follows the Data Type, Array Dimension and Number of Values
+//
+// Includes:
+// - Vector of Scalers
+// - Vector of Raw Buffer Widths
+func readDAQmxIndex(file io.ReadSeeker, digitalLine bool, order binary.ByteOrder) (*DAQmxIndex, error) {
+	numScalers, err := ReadUint32(file, 0, 1, order)
+	if err != nil {
+		return nil, err
+	}
+	log.Debugf("DAQmx Number of Scalers: %d\n", numScalers)
+
+	var scalers []DAQmxScaler
+	for i := uint32(0); i < numScalers; i++ {
+		dataType, err := ReadUint32(file, 0, 1, order)
+		if err != nil {
+			return nil, err
+		}
+		rawBufferIndex, err := ReadUint32(file, 0, 1, order)
+		if err != nil {
+			return nil, err
+		}
+		rawOffset, err := ReadUint32(file, 0, 1, order)
+		if err != nil {
+			return nil, err
+		}
+
+		// Digital Line Scalers have a single byte Sample Format
+		var sampleFormat uint32
+		if digitalLine {
+			sampleFormatBytes, _, err := readBytes(file, 1, 0, 1, "read DAQmx scaler")
+			if err != nil {
+				return nil, err
+			}
+			sampleFormat = uint32(sampleFormatBytes[0])
+		} else {
+			sampleFormat, err = ReadUint32(file, 0, 1, order)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		scaleID, err := ReadUint32(file, 0, 1, order)
+		if err != nil {
+			return nil, err
+		}
+
+		scalers = append(scalers, DAQmxScaler{
+			DAQmxDataType(dataType),
+			rawBufferIndex,
+			rawOffset,
+			sampleFormat,
+			scaleID,
+		})
+	}
+
+	numWidths, err := ReadUint32(file, 0, 1, order)
+	if err != nil {
+		return nil, err
+	}
+	// A corrupt count must not allocate more than is left to read
+	remaining, err := remainingBytes(file)
+	if err != nil {
+		return nil, newError("read DAQmx index", 0, err)
+	}
+	if int64(numWidths)*4 > remaining {
+		pos, _ := file.Seek(0, io.SeekCurrent)
+		err := fmt.Errorf("%w: %d raw data widths, %d bytes remain", ErrTruncatedSegment, numWidths, remaining)
+		return nil, newError("read DAQmx index", pos-4, err)
+	}
+	widths, err := ReadUint32Array(file, int64(numWidths), 0, 1, order)
+	if err != nil {
+		return nil, err
+	}
+	log.Debugf("DAQmx Raw Data Widths: %v\n", widths)
+
+	return &DAQmxIndex{
+		digitalLine,
+		scalers,
+		widths,
+	}, nil
+}
+
+// Reads the unscaled values of a DAQmx Channel from a Segment
+// Only the first Scaler of the Channel is read
+//
+// Returns a []bool for Digital Line Channels, otherwise a []float64
+func readSegmentDAQmxRaw(src io.ReaderAt, segment Segment, channelPath string) (interface{}, error) {
+	layout, err := channelLayout(segment, channelPath)
+	if err != nil {
+		return nil, err
+	}
+
+	digitalLine := segment.Objects[channelPath].RawDataIndex.DAQmx.DigitalLine
+	total := layout.numValues * segment.NumChunks
+	var values []float64
+	var lines []bool
+	if digitalLine {
+		lines = make([]bool, 0, total)
+	} else {
+		values = make([]float64, 0, total)
+	}
+
+	for chunk := uint64(0); chunk < segment.NumChunks; chunk++ {
+		count := chunkValues(segment, channelPath, layout, chunk)
+		data, err := readDAQmxValues(src, segment, channelPath, layout, chunk, 0, count)
+		if err != nil {
+			return nil, err
+		}
+		if digitalLine {
+			lines = append(lines, data.([]bool)...)
+		} else {
+			values = append(values, data.([]float64)...)
+		}
+	}
+
+	if digitalLine {
+		return lines, nil
+	}
+	return values, nil
+}
+
+// Reads count unscaled values of a DAQmx Channel from a Chunk,
+// starting at the start'th value of the Chunk
+//
+// Raw Buffers are stored one after the other in each Chunk,
+// each holding one sample of RawDataWidth bytes per value
+//
+// Returns a []bool for Digital Line Channels, otherwise a []float64
+func readDAQmxValues(src io.ReaderAt, segment Segment, channelPath string, layout chunkLayout, chunk uint64, start uint64, count uint64) (interface{}, error) {
+	daqmx := segment.Objects[channelPath].RawDataIndex.DAQmx
+	if len(daqmx.Scalers) == 0 {
+		err := fmt.Errorf("%w: DAQmx channel has no scalers", ErrUnsupportedDataType)
+		return nil, &Error{"read DAQmx data", int64(segment.DataPos), channelPath, err}
+	}
+	scaler := daqmx.Scalers[0]
+	if int(scaler.RawBufferIndex) >= len(daqmx.RawDataWidths) {
+		err := fmt.Errorf("%w: DAQmx scaler raw buffer %d does not exist", ErrInvalidChunkSize, scaler.RawBufferIndex)
+		return nil, &Error{"read DAQmx data", int64(segment.DataPos), channelPath, err}
+	}
+
+	// Offset of the Scaler's Raw Buffer from the start of the DAQmx data
+	bufferOffset := uint64(0)
+	for _, w := range daqmx.RawDataWidths[:scaler.RawBufferIndex] {
+		bufferOffset += uint64(w) * layout.numValues
+	}
+	bufferWidth := uint64(daqmx.RawDataWidths[scaler.RawBufferIndex])
+
+	valueOffset := uint64(scaler.RawOffset)
+	valueSize := uint64(scaler.DataType.Size())
+	if daqmx.DigitalLine {
+		valueOffset = uint64(scaler.RawOffset / 8)
+		valueSize = 1
+	}
+	if valueSize == 0 || (scaler.DataType == DAQmxTimestamp && !daqmx.DigitalLine) {
+		err := fmt.Errorf("%w: DAQmx data type 0x%X", ErrUnsupportedDataType, uint32(scaler.DataType))
+		return nil, &Error{"read DAQmx data", int64(segment.DataPos), channelPath, err}
+	}
+	if valueOffset+valueSize > bufferWidth {
+		err := fmt.Errorf("%w: DAQmx scaler offset is outside the raw buffer", ErrInvalidChunkSize)
+		return nil, &Error{"read DAQmx data", int64(segment.DataPos), channelPath, err}
+	}
+
+	pos := segment.DataPos + chunk*layout.chunkSize + layout.offset + bufferOffset + start*bufferWidth
+	buffer, err := readView(src, nil, bufferWidth*count, int64(pos), "read DAQmx data")
+	if err != nil {
+		return nil, withPath(err, channelPath)
+	}
+
+	if daqmx.DigitalLine {
+		lines := make([]bool, count)
+		for i := range lines {
+			lines[i] = buffer[uint64(i)*bufferWidth+valueOffset]&(1<<(scaler.RawOffset%8)) != 0
+		}
+		return lines, nil
+	}
+
+	order := segment.ByteOrder()
+	values := make([]float64, count)
+	for i := range values {
+		sample := buffer[uint64(i)*bufferWidth+valueOffset : uint64(i)*bufferWidth+valueOffset+valueSize]
+		values[i] = decodeDAQmxValue(sample, scaler.DataType, order)
+	}
+	return values, nil
+}
+
+// Decodes a single DAQmx value to a float64
+func decodeDAQmxValue(b []byte, dataType DAQmxDataType, order binary.ByteOrder) float64 {
+	switch dataType {
+	case DAQmxUint8:
+		return float64(b[0])
+	case DAQmxInt8:
+		return float64(int8(b[0]))
+	case DAQmxUint16:
+		return float64(order.Uint16(b))
+	case DAQmxInt16:
+		return float64(int16(order.Uint16(b)))
+	case DAQmxUint32:
+		return float64(order.Uint32(b))
+	case DAQmxInt32:
+		return float64(int32(order.Uint32(b)))
+	case DAQmxUint64:
+		return float64(order.Uint64(b))
+	case DAQmxInt64:
+		return float64(int64(order.Uint64(b)))
+	case DAQmxSGL:
+		return float64(math.Float32frombits(order.Uint32(b)))
+	case DAQmxDBL:
+		return math.Float64frombits(order.Uint64(b))
+	}
+	return math.NaN()
+}
+
+// Reads the values of a DAQmx Channel from a Segment
+// Applies the NI_Scale Properties of the Channel to the raw values
+//
+// Returns a []bool for Digital Line Channels, otherwise a []float64
+func ReadSegmentDAQmxData(src io.ReaderAt, segment Segment, channelPath string, properties map[string]Property) (interface{}, error) {
+	data, err := readSegmentDAQmxRaw(src, segment, channelPath)
+	if err != nil {
+		return nil, err
+	}
+
+	values, ok := data.([]float64)
+	if !ok {
+		return data, nil
+	}
+
+	scaling, err := ScalingFromProperties(properties)
+	if err != nil {
+		return nil, withPath(err, channelPath)
+	}
+
+	return scaling.Scale(values), nil
+}
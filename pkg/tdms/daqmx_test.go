@@ -0,0 +1,162 @@
+package tdms
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+// Appends a DAQmx Raw Data Index, header is daqmxFormatChangingScaler
+// or daqmxDigitalLineScaler
+func (s *segmentBuilder) daqmxIndex(header uint32, numValues uint64, scalers []DAQmxScaler, widths []uint32) {
+	s.u32(header)
+	s.u32(0xFFFFFFFF)
+	s.u32(1)
+	s.u64(numValues)
+	s.u32(uint32(len(scalers)))
+	for _, scaler := range scalers {
+		s.u32(uint32(scaler.DataType))
+		s.u32(scaler.RawBufferIndex)
+		s.u32(scaler.RawOffset)
+		if header == daqmxDigitalLineScaler {
+			s.b = append(s.b, byte(scaler.SampleFormat))
+		} else {
+			s.u32(scaler.SampleFormat)
+		}
+		s.u32(scaler.ScaleID)
+	}
+	s.u32(uint32(len(widths)))
+	for _, width := range widths {
+		s.u32(width)
+	}
+}
+
+func TestReadDAQmxFormatChanging(t *testing.T) {
+	for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		t.Run(order.String(), func(t *testing.T) {
+			a, b := ChannelPath("G", "A"), ChannelPath("G", "B")
+			// A is an Int16 and B an SGL, in a single Raw Buffer of 6 Bytes
+			scalerA := DAQmxScaler{DAQmxInt16, 0, 0, 0, 0}
+			scalerB := DAQmxScaler{DAQmxSGL, 0, 2, 0, 1}
+			widths := []uint32{6}
+
+			tocMask := KTocMetaData | KTocNewObjList | KTocRawData
+			if order == binary.BigEndian {
+				tocMask |= KTocBigEndian
+			}
+			data := appendTestSegment(nil, tocMask, func(s *segmentBuilder) {
+				s.u32(2)
+				s.str(a)
+				s.daqmxIndex(daqmxFormatChangingScaler, 3, []DAQmxScaler{scalerA}, widths)
+				// y = 2x + 1
+				s.u32(4)
+				s.str("NI_Number_Of_Scales")
+				s.u32(uint32(Int32))
+				s.u32(1)
+				s.str("NI_Scale[0]_Scale_Type")
+				s.u32(uint32(String))
+				s.str("Linear")
+				s.str("NI_Scale[0]_Linear_Slope")
+				s.u32(uint32(DBL))
+				s.u64(math.Float64bits(2))
+				s.str("NI_Scale[0]_Linear_Y_Intercept")
+				s.u32(uint32(DBL))
+				s.u64(math.Float64bits(1))
+				s.str(b)
+				s.daqmxIndex(daqmxFormatChangingScaler, 3, []DAQmxScaler{scalerB}, widths)
+				s.u32(0)
+			}, func(s *segmentBuilder) {
+				for i, v := range []int16{-3, 0, 7} {
+					var d []byte
+					s.b, d = extend(s.b, 2)
+					order.PutUint16(d, uint16(v))
+					s.u32(math.Float32bits(float32(i) + 0.25))
+				}
+			})
+
+			f, err := NewFile(NewBytesSource(data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			segment := f.Segments()[0]
+			want := &DAQmxIndex{false, []DAQmxScaler{scalerA}, widths}
+			if got := segment.Objects[a].RawDataIndex.DAQmx; !reflect.DeepEqual(got, want) {
+				t.Errorf("A index: got %+v, want %+v", got, want)
+			}
+			if got := segment.Objects[b].RawDataIndex.RawDataSize; got != 18 {
+				t.Errorf("B raw data size: got %d, want 18", got)
+			}
+
+			if got, want := channelData(t, f, "G", "A"), []float64{-5, 1, 15}; !reflect.DeepEqual(got, want) {
+				t.Errorf("A: got %v, want %v", got, want)
+			}
+			if got, want := channelData(t, f, "G", "B"), []float64{0.25, 1.25, 2.25}; !reflect.DeepEqual(got, want) {
+				t.Errorf("B: got %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestReadDAQmxDigitalLines(t *testing.T) {
+	// Lines at bits 0 and 3 of the first Byte, and bit 1 of the second,
+	// of a single Raw Buffer of 2 Bytes
+	lines := []DAQmxScaler{
+		{DAQmxUint8, 0, 0, 1, 0},
+		{DAQmxUint8, 0, 3, 1, 1},
+		{DAQmxUint8, 0, 9, 1, 2},
+	}
+	samples := [][2]byte{{0b1001, 0b10}, {0b0001, 0}, {0b1000, 0b10}}
+	want := [][]bool{{true, true, false}, {true, false, true}, {true, false, true}}
+
+	tocMask := KTocMetaData | KTocNewObjList | KTocRawData
+	data := appendTestSegment(nil, tocMask, func(s *segmentBuilder) {
+		s.u32(uint32(len(lines)))
+		for i, scaler := range lines {
+			s.str(ChannelPath("G", string(rune('A'+i))))
+			s.daqmxIndex(daqmxDigitalLineScaler, uint64(len(samples)), []DAQmxScaler{scaler}, []uint32{2})
+			s.u32(0)
+		}
+	}, func(s *segmentBuilder) {
+		for _, sample := range samples {
+			s.b = append(s.b, sample[:]...)
+		}
+	})
+
+	f, err := NewFile(NewBytesSource(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	index := f.Segments()[0].Objects[ChannelPath("G", "B")].RawDataIndex.DAQmx
+	if wantIndex := (&DAQmxIndex{true, lines[1:2], []uint32{2}}); !reflect.DeepEqual(index, wantIndex) {
+		t.Errorf("B index: got %+v, want %+v", index, wantIndex)
+	}
+	for i := range lines {
+		name := string(rune('A' + i))
+		if got := channelData(t, f, "G", name); !reflect.DeepEqual(got, want[i]) {
+			t.Errorf("%s: got %v, want %v", name, got, want[i])
+		}
+	}
+}
+
+func TestReadDAQmxWidthsPastMetadata(t *testing.T) {
+	path := ChannelPath("G", "A")
+	data := appendTestSegment(nil, KTocMetaData|KTocNewObjList|KTocRawData, func(s *segmentBuilder) {
+		s.u32(1)
+		s.str(path)
+		s.daqmxIndex(daqmxFormatChangingScaler, 1, []DAQmxScaler{{DAQmxInt16, 0, 0, 0, 0}}, []uint32{2})
+		s.u32(0)
+	}, func(s *segmentBuilder) {
+		s.u32(0)
+	})
+	// Number of Raw Buffer Widths, before the single width and the
+	// Number of Properties
+	metaEnd := leadInSize + int(binary.LittleEndian.Uint64(data[20:28]))
+	binary.LittleEndian.PutUint32(data[metaEnd-12:], 0xFFFFFFFF)
+
+	_, err := NewFile(NewBytesSource(data))
+	if !errors.Is(err, ErrTruncatedSegment) {
+		t.Errorf("got %v, want ErrTruncatedSegment", err)
+	}
+}
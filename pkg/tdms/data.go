@@ -0,0 +1,609 @@
+package tdms
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/big"
+	"math/cmplx"
+)
+
+// Where a Channel's values are within each Chunk of a Segment
+type chunkLayout struct {
+	// Byte Offset of the first value from the start of the Chunk
+	offset uint64
+	// Bytes from one value to the next
+	// Equal to width unless the data is Interleaved
+	stride uint64
+	// Bytes in a single value
+	width uint64
+	// Number of values in each Chunk
+	numValues uint64
+	// Bytes of the Channel in each Chunk, once de-interleaved
+	blockSize uint64
+	// Total Bytes in a Chunk, for all Objects
+	chunkSize uint64
+}
+
+// Finds the layout of a Channel's Raw Data in each Chunk of a Segment
+//
+// Contiguous Chunks hold each Object's values one after the other
+// in ObjectOrder, Interleaved Chunks hold one value of every Object
+// in ObjectOrder, repeated for each value
+// DAQmx Objects share a single block of Raw Buffers, the offset of
+// a DAQmx Channel is the start of that block
+func channelLayout(segment Segment, channelPath string) (chunkLayout, error) {
+	obj, present := segment.Objects[channelPath]
+	if !present || !obj.HasRawData() {
+		return chunkLayout{}, &Error{"read channel data", int64(segment.DataPos), channelPath, ErrNoChannelData}
+	}
+
+	var layout chunkLayout
+	found := false
+	daqmxSeen := false
+	daqmxOffset := uint64(0)
+	for _, path := range segment.ObjectOrder {
+		other := segment.Objects[path]
+		if !other.HasRawData() {
+			continue
+		}
+
+		if other.RawDataIndex.DAQmx != nil {
+			if !daqmxSeen {
+				daqmxSeen = true
+				daqmxOffset = layout.chunkSize
+				layout.chunkSize += other.RawDataIndex.RawDataSize
+			}
+			if path == channelPath {
+				found = true
+				layout.numValues = other.RawDataIndex.NumValues
+			}
+			continue
+		}
+
+		width := uint64(other.RawDataIndex.DataType.Size())
+		if segment.Interleaved() && width == 0 {
+			err := fmt.Errorf("%w: 0x%X can not be interleaved", ErrUnsupportedDataType, other.RawDataIndex.DataType)
+			return chunkLayout{}, &Error{"read channel data", int64(segment.DataPos), path, err}
+		}
+
+		if path == channelPath {
+			found = true
+			layout.width = width
+			layout.numValues = other.RawDataIndex.NumValues
+			layout.blockSize = other.RawDataIndex.RawDataSize
+			if segment.Interleaved() {
+				layout.offset = layout.stride
+			} else {
+				layout.offset = layout.chunkSize
+			}
+		}
+
+		if segment.Interleaved() {
+			layout.stride += width
+		}
+		layout.chunkSize += other.RawDataIndex.RawDataSize
+	}
+
+	if !found {
+		return chunkLayout{}, &Error{"read channel data", int64(segment.DataPos), channelPath, ErrNoChannelData}
+	}
+
+	if obj.RawDataIndex.DAQmx != nil {
+		layout.offset = daqmxOffset
+		return layout, nil
+	}
+
+	if !segment.Interleaved() {
+		layout.stride = layout.width
+	} else if layout.stride*layout.numValues != layout.chunkSize {
+		err := fmt.Errorf("%w: interleaved objects have different numbers of values", ErrInvalidChunkSize)
+		return chunkLayout{}, &Error{"read channel data", int64(segment.DataPos), channelPath, err}
+	}
+
+	return layout, nil
+}
+
+// Number of values of a Channel in a Chunk of a Segment
+//
+// Every Chunk holds layout.numValues values, except the final Chunk of a
+// truncated Segment, which only holds the values written before it was
+// cut short, see FinalChunkLengthOverride
+// Strings, and DAQmx Channels with several Raw Buffers, are only
+// recovered if all of their values were written
+func chunkValues(segment Segment, channelPath string, layout chunkLayout, chunk uint64) uint64 {
+	written := segment.FinalChunkLengthOverride
+	if written == 0 || chunk != segment.NumChunks-1 {
+		return layout.numValues
+	}
+	if written <= layout.offset {
+		return 0
+	}
+	// Bytes written from the Channel's first value
+	written -= layout.offset
+
+	index := segment.Objects[channelPath].RawDataIndex
+	n := uint64(0)
+	switch {
+	case index.DAQmx != nil && len(index.DAQmx.RawDataWidths) == 1 && index.DAQmx.RawDataWidths[0] > 0:
+		n = written / uint64(index.DAQmx.RawDataWidths[0])
+	case index.DAQmx != nil || layout.width == 0:
+		if written >= index.RawDataSize {
+			n = layout.numValues
+		}
+	case written >= layout.width:
+		n = (written-layout.width)/layout.stride + 1
+	}
+
+	if n > layout.numValues {
+		n = layout.numValues
+	}
+	return n
+}
+
+// Number of values of a Channel in a Segment
+// A truncated Segment whose layout can not be read has none, rather than
+// the number its Raw Data Index gives
+func segmentNumValues(segment Segment, channelPath string) uint64 {
+	total := segment.Objects[channelPath].RawDataIndex.NumValues * segment.NumChunks
+	if segment.FinalChunkLengthOverride == 0 || segment.NumChunks == 0 {
+		return total
+	}
+
+	layout, err := channelLayout(segment, channelPath)
+	if err != nil {
+		return 0
+	}
+	return total - layout.numValues + chunkValues(segment, channelPath, layout, segment.NumChunks-1)
+}
+
+// Reads the Raw Data of a Channel from every Chunk of a Segment
+// Interleaved Data is de-interleaved, leaving each value's bytes
+// one after the other
+//
+// Returns the Channel's Raw Bytes
+func ReadSegmentChannelBytes(src io.ReaderAt, segment Segment, channelPath string) ([]byte, error) {
+	layout, err := channelLayout(segment, channelPath)
+	if err != nil {
+		return nil, err
+	}
+	if segment.Objects[channelPath].RawDataIndex.DAQmx != nil {
+		err := fmt.Errorf("%w: DAQmx raw buffers are shared between channels", ErrUnsupportedDataType)
+		return nil, &Error{"read channel data", int64(segment.DataPos), channelPath, err}
+	}
+
+	// Bytes of the Channel in a Chunk, fewer in the final Chunk of a
+	// truncated Segment
+	chunkValueBytes := layout.blockSize
+	chunkBytesWritten := func(chunk uint64) uint64 {
+		n := chunkValues(segment, channelPath, layout, chunk)
+		if n == layout.numValues {
+			return chunkValueBytes
+		}
+		return n * layout.width
+	}
+
+	total := chunkValueBytes * segment.NumChunks
+	if segment.NumChunks > 0 {
+		total -= chunkValueBytes - chunkBytesWritten(segment.NumChunks-1)
+	}
+	channelBytes := make([]byte, total)
+
+	if !segment.Interleaved() {
+		// Each Chunk only requires the Channel's block of bytes
+		for chunk := uint64(0); chunk < segment.NumChunks; chunk++ {
+			pos := segment.DataPos + chunk*layout.chunkSize + layout.offset
+			dst := channelBytes[chunk*chunkValueBytes : chunk*chunkValueBytes+chunkBytesWritten(chunk)]
+			err := readAt(src, dst, int64(pos), "read channel data")
+			if err != nil {
+				return nil, withPath(err, channelPath)
+			}
+		}
+		return channelBytes, nil
+	}
+
+	// Interleaved Chunks are read whole, then the Channel's values copied out
+	var chunkBytes []byte
+	for chunk := uint64(0); chunk < segment.NumChunks; chunk++ {
+		pos := segment.DataPos + chunk*layout.chunkSize
+		size := layout.chunkSize
+		if chunk == segment.NumChunks-1 && segment.FinalChunkLengthOverride != 0 {
+			size = segment.FinalChunkLengthOverride
+		}
+		chunkBytes, err = readView(src, chunkBytes, size, int64(pos), "read channel data")
+		if err != nil {
+			return nil, withPath(err, channelPath)
+		}
+		chunkLayout := layout
+		chunkLayout.numValues = chunkValues(segment, channelPath, layout, chunk)
+		if chunkLayout.numValues == 0 {
+			continue
+		}
+		dst := channelBytes[chunk*chunkValueBytes : chunk*chunkValueBytes+chunkLayout.numValues*layout.width]
+		deinterleave(dst, chunkBytes, chunkLayout)
+	}
+
+	return channelBytes, nil
+}
+
+// Copies a single Object's values from an Interleaved Chunk
+func deinterleave(dst []byte, chunk []byte, layout chunkLayout) {
+	width := layout.width
+	stride := layout.stride
+	src := chunk[layout.offset:]
+
+	switch width {
+	case 1:
+		for i := range dst {
+			dst[i] = src[uint64(i)*stride]
+		}
+	case 4:
+		for i := uint64(0); i < layout.numValues; i++ {
+			s := src[i*stride : i*stride+4]
+			d := dst[i*4 : i*4+4]
+			d[0], d[1], d[2], d[3] = s[0], s[1], s[2], s[3]
+		}
+	case 8:
+		for i := uint64(0); i < layout.numValues; i++ {
+			s := src[i*stride : i*stride+8]
+			d := dst[i*8 : i*8+8]
+			d[0], d[1], d[2], d[3], d[4], d[5], d[6], d[7] = s[0], s[1], s[2], s[3], s[4], s[5], s[6], s[7]
+		}
+	default:
+		for i := uint64(0); i < layout.numValues; i++ {
+			copy(dst[i*width:(i+1)*width], src[i*stride:i*stride+width])
+		}
+	}
+}
+
+// Reads the values of a Channel from a Segment
+// DAQmx Channels are not scaled, see ReadSegmentDAQmxData
+//
+// Returns a slice of the Channel's Data Type:
+// - Int8 to Int64 and Uint8 to Uint64 as []int8 to []uint64
+// - SGL and DBL as []float32 and []float64
+// - EXT as []*big.Float, nil for NaN
+// - Boolean as []bool
+// - ComplexSGL and ComplexDBL as []complex64 and []complex128
+// - Timestamp as []LVTimestamp
+// - String as []string
+// DAQmx Channels return a []float64, or a []bool for Digital Lines
+func ReadSegmentChannelData(src io.ReaderAt, segment Segment, channelPath string) (interface{}, error) {
+	if obj, present := segment.Objects[channelPath]; present && obj.RawDataIndex.DAQmx != nil {
+		return readSegmentDAQmxRaw(src, segment, channelPath)
+	}
+
+	channelBytes, err := segmentChannelView(src, segment, channelPath)
+	if err != nil {
+		return nil, err
+	}
+
+	order := segment.ByteOrder()
+	index := segment.Objects[channelPath].RawDataIndex
+	dataType := index.DataType
+	if dataType == String {
+		// Each Chunk has its own Offset Table
+		// The final Chunk of a truncated Segment is left out if its
+		// strings were not all written
+		numChunks := segment.NumChunks
+		if uint64(len(channelBytes)) < index.RawDataSize*numChunks {
+			numChunks--
+		}
+		values := make([]string, 0, index.NumValues*numChunks)
+		for chunk := uint64(0); chunk < numChunks; chunk++ {
+			block := channelBytes[chunk*index.RawDataSize : (chunk+1)*index.RawDataSize]
+			chunkValues, err := decodeStringArray(block, index.NumValues, order)
+			if err != nil {
+				pos := int64(segment.DataPos + chunk*chunkSize(segment.Objects))
+				return nil, &Error{"read string data", pos, channelPath, err}
+			}
+			values = append(values, chunkValues...)
+		}
+		return values, nil
+	}
+
+	if values, ok := decodeArray(dataType, channelBytes, order); ok {
+		return values, nil
+	}
+
+	err = fmt.Errorf("%w: 0x%X", ErrUnsupportedDataType, dataType)
+	return nil, &Error{"read channel data", int64(segment.DataPos), channelPath, err}
+}
+
+// Reads count values of a Channel from a single Chunk of a Segment,
+// starting at the start'th value of the Chunk
+// Only the bytes of the requested values are read
+// DAQmx Channels are not scaled
+//
+// Returns a slice of the Channel's Data Type, see ReadSegmentChannelData
+func ReadChunkChannelData(src io.ReaderAt, segment Segment, channelPath string, chunk uint64, start uint64, count uint64) (interface{}, error) {
+	layout, err := channelLayout(segment, channelPath)
+	if err != nil {
+		return nil, err
+	}
+	numValues := chunkValues(segment, channelPath, layout, chunk)
+	if chunk >= segment.NumChunks || start > numValues || count > numValues-start {
+		err := fmt.Errorf("%w: values %d to %d of chunk %d", ErrOutOfRange, start, start+count, chunk)
+		return nil, &Error{"read channel data", int64(segment.DataPos), channelPath, err}
+	}
+
+	return readChunkValues(src, segment, channelPath, layout, chunk, start, count)
+}
+
+// Reads count values of a Channel from a Chunk with a known layout
+func readChunkValues(src io.ReaderAt, segment Segment, channelPath string, layout chunkLayout, chunk uint64, start uint64, count uint64) (interface{}, error) {
+	index := segment.Objects[channelPath].RawDataIndex
+	if index.DAQmx != nil {
+		return readDAQmxValues(src, segment, channelPath, layout, chunk, start, count)
+	}
+
+	order := segment.ByteOrder()
+	chunkPos := segment.DataPos + chunk*layout.chunkSize
+	if index.DataType == String {
+		return readStringValues(src, chunkPos+layout.offset, channelPath, layout, start, count, order)
+	}
+
+	var channelBytes []byte
+	var err error
+	pos := chunkPos + layout.offset + start*layout.stride
+	if !segment.Interleaved() {
+		channelBytes, err = readView(src, nil, count*layout.width, int64(pos), "read channel data")
+		if err != nil {
+			return nil, withPath(err, channelPath)
+		}
+	} else if count > 0 {
+		// Only the span from the first to last requested value is read
+		span, err := readView(src, nil, (count-1)*layout.stride+layout.width, int64(pos), "read channel data")
+		if err != nil {
+			return nil, withPath(err, channelPath)
+		}
+		spanLayout := layout
+		spanLayout.offset = 0
+		spanLayout.numValues = count
+		channelBytes = make([]byte, count*layout.width)
+		deinterleave(channelBytes, span, spanLayout)
+	}
+
+	if values, ok := decodeArray(index.DataType, channelBytes, order); ok {
+		return values, nil
+	}
+
+	err = fmt.Errorf("%w: 0x%X", ErrUnsupportedDataType, index.DataType)
+	return nil, &Error{"read channel data", int64(segment.DataPos), channelPath, err}
+}
+
+// Reads count values of a String Channel from a Chunk,
+// using the Offset Table to only read the requested strings
+func readStringValues(src io.ReaderAt, blockPos uint64, channelPath string, layout chunkLayout, start uint64, count uint64, order binary.ByteOrder) ([]string, error) {
+	if count == 0 {
+		return []string{}, nil
+	}
+	tableSize := layout.numValues * 4
+
+	// End offsets of the string before start, through the last string
+	tableStart := start
+	if start > 0 {
+		tableStart = start - 1
+	}
+	table, err := readView(src, nil, (start+count-tableStart)*4, int64(blockPos+tableStart*4), "read string data")
+	if err != nil {
+		return nil, withPath(err, channelPath)
+	}
+
+	dataStart := uint64(0)
+	if start > 0 {
+		dataStart = uint64(order.Uint32(table))
+		table = table[4:]
+	}
+	dataEnd := uint64(order.Uint32(table[len(table)-4:]))
+	if dataEnd < dataStart || tableSize+dataEnd > layout.blockSize {
+		err := fmt.Errorf("%w: strings end at %d of %d bytes", ErrInvalidChunkSize, dataEnd, layout.blockSize-tableSize)
+		return nil, &Error{"read string data", int64(blockPos), channelPath, err}
+	}
+
+	stringData, err := readView(src, nil, dataEnd-dataStart, int64(blockPos+tableSize+dataStart), "read string data")
+	if err != nil {
+		return nil, withPath(err, channelPath)
+	}
+
+	values := make([]string, count)
+	prev := dataStart
+	for i := range values {
+		end := uint64(order.Uint32(table[i*4:]))
+		if end < prev || end > dataEnd {
+			err := fmt.Errorf("%w: string %d ends at %d of %d bytes", ErrInvalidChunkSize, start+uint64(i), end, dataEnd)
+			return nil, &Error{"read string data", int64(blockPos), channelPath, err}
+		}
+		values[i] = string(stringData[prev-dataStart : end-dataStart])
+		prev = end
+	}
+	return values, nil
+}
+
+// Reads the values of a numeric Channel from a Segment
+// Booleans are converted to 0 or 1, Complex values to their magnitude
+//
+// Returns the values converted to []float64
+func ReadSegmentChannelFloat64(src io.ReaderAt, segment Segment, channelPath string) ([]float64, error) {
+	data, err := ReadSegmentChannelData(src, segment, channelPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if converted, ok := toFloat64Slice(data); ok {
+		return converted, nil
+	}
+
+	dataType := segment.Objects[channelPath].RawDataIndex.DataType
+	err = fmt.Errorf("%w: 0x%X is not numeric", ErrUnsupportedDataType, dataType)
+	return nil, &Error{"read channel data", int64(segment.DataPos), channelPath, err}
+}
+
+// Reads len(p) bytes from src at the given offset
+func readAt(src io.ReaderAt, p []byte, offset int64, op string) error {
+	n, err := src.ReadAt(p, offset)
+	if n == len(p) {
+		return nil
+	}
+	if err == nil {
+		err = io.ErrUnexpectedEOF
+	}
+	return newError(op, offset, err)
+}
+
+// Reads n bytes from src at the given offset, to be decoded but not modified
+// Sources held in memory return a slice of their bytes without copying,
+// otherwise the bytes are read into buf, which is reused if large enough
+func readView(src io.ReaderAt, buf []byte, n uint64, offset int64, op string) ([]byte, error) {
+	if mem, ok := src.(memorySource); ok {
+		b := mem.Bytes()
+		if offset < 0 || uint64(offset)+n > uint64(len(b)) {
+			return nil, newError(op, offset, ErrTruncatedSegment)
+		}
+		return b[offset : uint64(offset)+n], nil
+	}
+
+	buf = growBuffer(buf, int64(n))
+	err := readAt(src, buf, offset, op)
+	if err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+// Reads the Raw Data of a Channel in a Segment for decoding
+// A single contiguous Chunk is read with readView, so is not copied
+// from Sources held in memory
+func segmentChannelView(src io.ReaderAt, segment Segment, channelPath string) ([]byte, error) {
+	if segment.Interleaved() || segment.NumChunks != 1 || segment.FinalChunkLengthOverride != 0 {
+		return ReadSegmentChannelBytes(src, segment, channelPath)
+	}
+
+	layout, err := channelLayout(segment, channelPath)
+	if err != nil {
+		return nil, err
+	}
+	b, err := readView(src, nil, layout.blockSize, int64(segment.DataPos+layout.offset), "read channel data")
+	if err != nil {
+		return nil, withPath(err, channelPath)
+	}
+	return b, nil
+}
+
+// Converts a slice of numeric values to []float64
+// Returns false if the values are not numeric
+func toFloat64Slice(data interface{}) ([]float64, bool) {
+	var converted []float64
+	switch values := data.(type) {
+	case []float64:
+		return values, true
+	case []float32:
+		converted = make([]float64, len(values))
+		for i, v := range values {
+			converted[i] = float64(v)
+		}
+	case []int8:
+		converted = make([]float64, len(values))
+		for i, v := range values {
+			converted[i] = float64(v)
+		}
+	case []int16:
+		converted = make([]float64, len(values))
+		for i, v := range values {
+			converted[i] = float64(v)
+		}
+	case []int32:
+		converted = make([]float64, len(values))
+		for i, v := range values {
+			converted[i] = float64(v)
+		}
+	case []int64:
+		converted = make([]float64, len(values))
+		for i, v := range values {
+			converted[i] = float64(v)
+		}
+	case []uint8:
+		converted = make([]float64, len(values))
+		for i, v := range values {
+			converted[i] = float64(v)
+		}
+	case []uint16:
+		converted = make([]float64, len(values))
+		for i, v := range values {
+			converted[i] = float64(v)
+		}
+	case []uint32:
+		converted = make([]float64, len(values))
+		for i, v := range values {
+			converted[i] = float64(v)
+		}
+	case []uint64:
+		converted = make([]float64, len(values))
+		for i, v := range values {
+			converted[i] = float64(v)
+		}
+	case []*big.Float:
+		converted = make([]float64, len(values))
+		for i, v := range values {
+			converted[i] = extFloat64(v)
+		}
+	case []bool:
+		converted = make([]float64, len(values))
+		for i, v := range values {
+			if v {
+				converted[i] = 1
+			}
+		}
+	case []complex64:
+		converted = make([]float64, len(values))
+		for i, v := range values {
+			converted[i] = cmplx.Abs(complex128(v))
+		}
+	case []complex128:
+		converted = make([]float64, len(values))
+		for i, v := range values {
+			converted[i] = cmplx.Abs(v)
+		}
+	default:
+		return nil, false
+	}
+	return converted, true
+}
+
+// Decodes Raw Bytes of a fixed width Data Type into a slice of that type
+// Returns false if the Data Type is not fixed width
+func decodeArray(dataType TdsDataType, b []byte, order binary.ByteOrder) (interface{}, bool) {
+	switch dataType {
+	case Int8:
+		return decodeInt8Array(b), true
+	case Int16:
+		return decodeInt16Array(b, order), true
+	case Int32:
+		return decodeInt32Array(b, order), true
+	case Int64:
+		return decodeInt64Array(b, order), true
+	case Uint8:
+		return decodeUint8Array(b), true
+	case Uint16:
+		return decodeUint16Array(b, order), true
+	case Uint32:
+		return decodeUint32Array(b, order), true
+	case Uint64:
+		return decodeUint64Array(b, order), true
+	case SGL, SGLwUnit:
+		return decodeSGLArray(b, order), true
+	case DBL, DBLwUnit:
+		return decodeDBLArray(b, order), true
+	case EXT, EXTwUnit:
+		return decodeEXTBigArray(b, order), true
+	case Boolean:
+		return decodeBooleanArray(b), true
+	case ComplexSGL:
+		return decodeComplexSGLArray(b, order), true
+	case ComplexDBL:
+		return decodeComplexDBLArray(b, order), true
+	case Timestamp:
+		return decodeTimestampArray(b, order), true
+	}
+	return nil, false
+}
@@ -0,0 +1,198 @@
+package tdms
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Rewrites a TDMS File as a single Segment for each Group, like NI's
+// TDMS Defragment
+//
+// Each Segment holds a Group, its Channels and their latest Properties,
+// with every value of each Channel in a single Chunk
+// The first Segment also holds the File's Properties
+// DAQmx Channels are written as their scaled values, without the
+// Properties describing their Scaling
+func Defragment(f *File, w io.Writer) error {
+	if len(f.groups) == 0 {
+		return defragmentGroup(f, nil, w)
+	}
+	for _, group := range f.groups {
+		err := defragmentGroup(f, group, w)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// A Channel written by defragmentGroup
+type defragmentChannel struct {
+	channel  *Channel
+	dataType TdsDataType
+	// Strings are read up front, as their size is written in the Metadata
+	strings []string
+}
+
+// Writes the Segment of a single Group
+// The File's Properties are written with the first Group, or on their
+// own if group is nil
+func defragmentGroup(f *File, group *Group, w io.Writer) error {
+	order := binary.LittleEndian
+
+	// Number of Objects is set once known
+	meta := make([]byte, 4)
+	numObjects := uint32(0)
+	var err error
+	if group == nil || group == f.groups[0] {
+		meta, err = appendObject(meta, rootPath, NoRawDataValue, sortedProperties(f.Properties()), order)
+		if err != nil {
+			return &Error{"write metadata", 0, rootPath, err}
+		}
+		numObjects++
+	}
+
+	var channels []defragmentChannel
+	dataLength := uint64(0)
+	if group != nil {
+		meta, err = appendObject(meta, group.path, NoRawDataValue, sortedProperties(group.Properties()), order)
+		if err != nil {
+			return &Error{"write metadata", 0, group.path, err}
+		}
+		numObjects++
+
+		for _, c := range group.channels {
+			channel, properties, err := defragmentChannelOf(c)
+			if err != nil {
+				return err
+			}
+
+			rawDataIndex := NoRawDataValue
+			numValues := c.NumValues()
+			if numValues > 0 {
+				size := numValues * uint64(channel.dataType.Size())
+				if channel.dataType == String {
+					size = arraySize(String, channel.strings)
+				}
+				rawDataIndex = appendRawDataIndex(nil, channel.dataType, numValues, size, order)
+				dataLength += size
+				channels = append(channels, channel)
+			}
+
+			meta, err = appendObject(meta, c.path, rawDataIndex, properties, order)
+			if err != nil {
+				return &Error{"write metadata", 0, c.path, err}
+			}
+			numObjects++
+		}
+	}
+	order.PutUint32(meta, numObjects)
+
+	tocMask := KTocMetaData | KTocNewObjList
+	if dataLength > 0 {
+		tocMask |= KTocRawData
+	}
+	segment := appendLeadIn(nil, tocMask, uint64(len(meta)), dataLength)
+	segment = append(segment, meta...)
+	_, err = w.Write(segment)
+	if err != nil {
+		return newError("write segment", 0, err)
+	}
+
+	// Channels are written one Block at a time
+	var buf []byte
+	for _, channel := range channels {
+		if channel.dataType == String {
+			buf, err = appendArray(buf[:0], String, channel.strings, order)
+			if err == nil {
+				_, err = w.Write(buf)
+			}
+			if err != nil {
+				return &Error{"write channel data", 0, channel.channel.path, err}
+			}
+			continue
+		}
+
+		it := channel.channel.Iterator(0)
+		for {
+			block, err := it.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return err
+			}
+			buf, err = appendArray(buf[:0], channel.dataType, block.Values, order)
+			if err == nil {
+				_, err = w.Write(buf)
+			}
+			if err != nil {
+				return &Error{"write channel data", 0, channel.channel.path, err}
+			}
+		}
+	}
+	return nil
+}
+
+// Finds the Data Type and Properties a Channel is written with
+// DAQmx Channels are written as DBL, or Boolean for Digital Lines
+func defragmentChannelOf(c *Channel) (defragmentChannel, []Property, error) {
+	channel := defragmentChannel{c, c.DataType(), nil}
+	properties := c.Properties()
+
+	switch {
+	case channel.dataType == DAQmx:
+		channel.dataType = DBL
+		for _, segment := range c.file.segments {
+			if obj, present := segment.Objects[c.path]; present && obj.RawDataIndex.DAQmx != nil && obj.RawDataIndex.DAQmx.DigitalLine {
+				channel.dataType = Boolean
+			}
+		}
+
+		// The values written are already scaled
+		scaled := make(map[string]Property)
+		for name, property := range properties {
+			if !strings.HasPrefix(name, "NI_Scal") && name != "NI_Number_Of_Scales" {
+				scaled[name] = property
+			}
+		}
+		properties = scaled
+	case channel.dataType == String && c.NumValues() > 0:
+		data, err := c.Data()
+		if err != nil {
+			return channel, nil, err
+		}
+		channel.strings = data.([]string)
+	case channel.dataType != Void && channel.dataType.Size() == 0:
+		err := fmt.Errorf("%w: 0x%X", ErrUnsupportedDataType, channel.dataType)
+		return channel, nil, &Error{"defragment channel", 0, c.path, err}
+	}
+	return channel, sortedProperties(properties), nil
+}
+
+// Writes a defragmented copy of the TDMS File name to outName, see
+// Defragment, along with its .tdms_index File
+// An existing outName is only replaced once the copy is complete
+func DefragmentFile(name string, outName string) error {
+	fi, err := os.Stat(name)
+	if err != nil {
+		return err
+	}
+	f, err := Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	err = writeFileAtomic(outName, fi.Mode().Perm(), func(w io.Writer) error {
+		return Defragment(f, w)
+	})
+	if err != nil {
+		return err
+	}
+	_, err = WriteIndexFile(outName)
+	return err
+}
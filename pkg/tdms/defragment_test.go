@@ -0,0 +1,102 @@
+package tdms
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+// Values of each Property, which are compared instead of where they
+// were read from
+func propertyValues(properties map[string]Property) map[string]interface{} {
+	values := make(map[string]interface{})
+	for name, property := range properties {
+		values[name] = property.Value()
+	}
+	return values
+}
+
+func TestDefragment(t *testing.T) {
+	a, b, s := ChannelPath("G1", "A"), ChannelPath("G1", "B"), ChannelPath("G2", "S")
+	ts := ChannelPath("G2", "T")
+	title, _ := NewProperty("title", "fragments")
+	volts, _ := NewProperty("unit", "V")
+	millivolts, _ := NewProperty("unit", "mV")
+	gain, _ := NewProperty("gain", 2.5)
+
+	// Segments contiguous, interleaved and big endian, with Properties
+	// changed along the way
+	data := writeTestFile(t, []WriteObject{
+		{rootPath, []Property{title}, nil},
+		{GroupPath("G1"), []Property{gain}, nil},
+		{a, []Property{volts}, []float64{1, 2}},
+		{b, nil, []int16{3}},
+		{s, nil, []string{"a"}},
+	})
+	data = append(data, writeTestFileMode(t, true, false, []WriteObject{
+		{a, []Property{millivolts}, []float64{3, 4}},
+		{b, nil, []int16{5, 6}},
+	})...)
+	data = append(data, writeTestFileMode(t, false, true, []WriteObject{
+		{s, nil, []string{"bc", ""}},
+		{ts, nil, []LVTimestamp{{1, 2}}},
+		{a, nil, []float64{5}},
+	})...)
+	// Multiple Chunks, and a truncated final Segment
+	data = append(data, iteratorTestFile()...)
+
+	f, err := NewFile(NewBytesSource(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := Defragment(f, &buf); err != nil {
+		t.Fatal(err)
+	}
+	defragmented := readTestFile(t, buf.Bytes())
+
+	if got, want := len(defragmented.Segments()), len(f.Groups()); got != want {
+		t.Fatalf("got %d segments, want one for each of %d groups", got, want)
+	}
+	for i, segment := range defragmented.Segments() {
+		group := f.Groups()[i]
+		if segment.NumChunks > 1 {
+			t.Errorf("%s: got %d chunks, want at most 1", group.Name(), segment.NumChunks)
+		}
+		for _, path := range segment.ObjectOrder {
+			names, _ := ParsePath(path)
+			if len(names) > 0 && names[0] != group.Name() {
+				t.Errorf("%s: segment holds %s", group.Name(), path)
+			}
+		}
+	}
+
+	if got, want := propertyValues(defragmented.Properties()), propertyValues(f.Properties()); !reflect.DeepEqual(got, want) {
+		t.Errorf("file properties: got %v, want %v", got, want)
+	}
+	for i, group := range f.Groups() {
+		got := defragmented.Groups()[i]
+		if got.Name() != group.Name() {
+			t.Fatalf("group %d: got %s, want %s", i, got.Name(), group.Name())
+		}
+		if !reflect.DeepEqual(propertyValues(got.Properties()), propertyValues(group.Properties())) {
+			t.Errorf("%s properties: got %v, want %v", group.Name(), got.Properties(), group.Properties())
+		}
+		if len(got.Channels()) != len(group.Channels()) {
+			t.Fatalf("%s: got %d channels, want %d", group.Name(), len(got.Channels()), len(group.Channels()))
+		}
+		for j, channel := range group.Channels() {
+			gotChannel := got.Channels()[j]
+			if gotChannel.Name() != channel.Name() {
+				t.Fatalf("%s channel %d: got %s, want %s", group.Name(), j, gotChannel.Name(), channel.Name())
+			}
+			want := channelData(t, f, group.Name(), channel.Name())
+			if values := channelData(t, defragmented, group.Name(), channel.Name()); !reflect.DeepEqual(values, want) {
+				t.Errorf("%s: got %v, want %v", channel.path, values, want)
+			}
+			if !reflect.DeepEqual(propertyValues(gotChannel.Properties()), propertyValues(channel.Properties())) {
+				t.Errorf("%s properties: got %v, want %v", channel.path, gotChannel.Properties(), channel.Properties())
+			}
+		}
+	}
+}
@@ -0,0 +1,515 @@
+package tdms
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"time"
+)
+
+// Data Type written for a slice of values
+//
+// The Go types are those returned when reading, see ReadSegmentChannelData,
+// []time.Time is also written as Timestamp
+// Returns false if the values can not be written
+func sliceDataType(values interface{}) (TdsDataType, bool) {
+	switch values.(type) {
+	case []int8:
+		return Int8, true
+	case []int16:
+		return Int16, true
+	case []int32:
+		return Int32, true
+	case []int64:
+		return Int64, true
+	case []uint8:
+		return Uint8, true
+	case []uint16:
+		return Uint16, true
+	case []uint32:
+		return Uint32, true
+	case []uint64:
+		return Uint64, true
+	case []float32:
+		return SGL, true
+	case []float64:
+		return DBL, true
+	case []*big.Float:
+		return EXT, true
+	case []bool:
+		return Boolean, true
+	case []complex64:
+		return ComplexSGL, true
+	case []complex128:
+		return ComplexDBL, true
+	case []LVTimestamp, []time.Time:
+		return Timestamp, true
+	case []string:
+		return String, true
+	}
+	return Void, false
+}
+
+// Data Type written for a single value, see Property.Value
+// time.Time is also written as Timestamp, nil as Void
+// Returns false if the value can not be written
+func valueDataType(value interface{}) (TdsDataType, bool) {
+	switch value.(type) {
+	case nil:
+		return Void, true
+	case int8:
+		return Int8, true
+	case int16:
+		return Int16, true
+	case int32:
+		return Int32, true
+	case int64:
+		return Int64, true
+	case uint8:
+		return Uint8, true
+	case uint16:
+		return Uint16, true
+	case uint32:
+		return Uint32, true
+	case uint64:
+		return Uint64, true
+	case float32:
+		return SGL, true
+	case float64:
+		return DBL, true
+	case *big.Float:
+		return EXT, true
+	case bool:
+		return Boolean, true
+	case complex64:
+		return ComplexSGL, true
+	case complex128:
+		return ComplexDBL, true
+	case LVTimestamp, time.Time:
+		return Timestamp, true
+	case string:
+		return String, true
+	}
+	return Void, false
+}
+
+// Data Type without a unit, values of both are written the same way
+func baseDataType(dataType TdsDataType) TdsDataType {
+	switch dataType {
+	case SGLwUnit:
+		return SGL
+	case DBLwUnit:
+		return DBL
+	case EXTwUnit:
+		return EXT
+	}
+	return dataType
+}
+
+// Extends b by n bytes
+// Returns the extended slice, and the n new bytes to write to, which
+// are not zeroed
+func extend(b []byte, n int) ([]byte, []byte) {
+	total := len(b) + n
+	if total > cap(b) {
+		grown := make([]byte, len(b), total+total/4)
+		copy(grown, b)
+		b = grown
+	}
+	b = b[:total]
+	return b, b[total-n:]
+}
+
+// Appends a slice of values of the Data Type to b, as Raw Data
+// Strings are written with their Offset Table
+func appendArray(b []byte, dataType TdsDataType, values interface{}, order binary.ByteOrder) ([]byte, error) {
+	if want, ok := sliceDataType(values); !ok || want != baseDataType(dataType) {
+		return b, fmt.Errorf("%w: %T can not be written as 0x%X", ErrUnsupportedDataType, values, dataType)
+	}
+
+	var d []byte
+	switch v := values.(type) {
+	case []int8:
+		b, d = extend(b, len(v))
+		for i, x := range v {
+			d[i] = byte(x)
+		}
+	case []uint8:
+		b = append(b, v...)
+	case []bool:
+		b, d = extend(b, len(v))
+		for i, x := range v {
+			d[i] = 0
+			if x {
+				d[i] = 1
+			}
+		}
+	case []int16:
+		b, d = extend(b, len(v)*2)
+		for i, x := range v {
+			order.PutUint16(d[i*2:], uint16(x))
+		}
+	case []uint16:
+		b, d = extend(b, len(v)*2)
+		for i, x := range v {
+			order.PutUint16(d[i*2:], x)
+		}
+	case []int32:
+		b, d = extend(b, len(v)*4)
+		for i, x := range v {
+			order.PutUint32(d[i*4:], uint32(x))
+		}
+	case []uint32:
+		b, d = extend(b, len(v)*4)
+		for i, x := range v {
+			order.PutUint32(d[i*4:], x)
+		}
+	case []float32:
+		b, d = extend(b, len(v)*4)
+		for i, x := range v {
+			order.PutUint32(d[i*4:], math.Float32bits(x))
+		}
+	case []int64:
+		b, d = extend(b, len(v)*8)
+		for i, x := range v {
+			order.PutUint64(d[i*8:], uint64(x))
+		}
+	case []uint64:
+		b, d = extend(b, len(v)*8)
+		for i, x := range v {
+			order.PutUint64(d[i*8:], x)
+		}
+	case []float64:
+		b, d = extend(b, len(v)*8)
+		for i, x := range v {
+			order.PutUint64(d[i*8:], math.Float64bits(x))
+		}
+	case []complex64:
+		b, d = extend(b, len(v)*8)
+		for i, x := range v {
+			order.PutUint32(d[i*8:], math.Float32bits(real(x)))
+			order.PutUint32(d[i*8+4:], math.Float32bits(imag(x)))
+		}
+	case []complex128:
+		b, d = extend(b, len(v)*16)
+		for i, x := range v {
+			order.PutUint64(d[i*16:], math.Float64bits(real(x)))
+			order.PutUint64(d[i*16+8:], math.Float64bits(imag(x)))
+		}
+	case []*big.Float:
+		for _, x := range v {
+			b = appendEXT(b, x, order)
+		}
+	case []LVTimestamp:
+		for _, x := range v {
+			b = appendTimestamp(b, x, order)
+		}
+	case []time.Time:
+		for _, x := range v {
+			b = appendTimestamp(b, LVTimestampFromTime(x), order)
+		}
+	case []string:
+		// Offset Table of each string's end, then the strings
+		b, d = extend(b, len(v)*4)
+		end := uint32(0)
+		for i, x := range v {
+			end += uint32(len(x))
+			order.PutUint32(d[i*4:], end)
+		}
+		for _, x := range v {
+			b = append(b, x...)
+		}
+	}
+	return b, nil
+}
+
+// Bytes of Raw Data written for a slice of values of the Data Type
+func arraySize(dataType TdsDataType, values interface{}) uint64 {
+	if strings, ok := values.([]string); ok {
+		size := uint64(len(strings)) * 4
+		for _, s := range strings {
+			size += uint64(len(s))
+		}
+		return size
+	}
+	return uint64(dataType.Size()) * arrayLength(values)
+}
+
+// Number of values in a slice of values to write
+func arrayLength(values interface{}) uint64 {
+	switch v := values.(type) {
+	case []int8:
+		return uint64(len(v))
+	case []int16:
+		return uint64(len(v))
+	case []int32:
+		return uint64(len(v))
+	case []int64:
+		return uint64(len(v))
+	case []uint8:
+		return uint64(len(v))
+	case []uint16:
+		return uint64(len(v))
+	case []uint32:
+		return uint64(len(v))
+	case []uint64:
+		return uint64(len(v))
+	case []float32:
+		return uint64(len(v))
+	case []float64:
+		return uint64(len(v))
+	case []*big.Float:
+		return uint64(len(v))
+	case []bool:
+		return uint64(len(v))
+	case []complex64:
+		return uint64(len(v))
+	case []complex128:
+		return uint64(len(v))
+	case []LVTimestamp:
+		return uint64(len(v))
+	case []time.Time:
+		return uint64(len(v))
+	case []string:
+		return uint64(len(v))
+	}
+	return 0
+}
+
+// Appends a single value of the Data Type to b, as a Property value
+func appendValue(b []byte, dataType TdsDataType, value interface{}, order binary.ByteOrder) ([]byte, error) {
+	if want, ok := valueDataType(value); !ok || want != baseDataType(dataType) {
+		return b, fmt.Errorf("%w: %T can not be written as 0x%X", ErrUnsupportedDataType, value, dataType)
+	}
+
+	var d []byte
+	switch v := value.(type) {
+	case nil:
+	case int8:
+		b = append(b, byte(v))
+	case uint8:
+		b = append(b, v)
+	case bool:
+		if v {
+			b = append(b, 1)
+		} else {
+			b = append(b, 0)
+		}
+	case int16:
+		b, d = extend(b, 2)
+		order.PutUint16(d, uint16(v))
+	case uint16:
+		b, d = extend(b, 2)
+		order.PutUint16(d, v)
+	case int32:
+		b, d = extend(b, 4)
+		order.PutUint32(d, uint32(v))
+	case uint32:
+		b, d = extend(b, 4)
+		order.PutUint32(d, v)
+	case float32:
+		b, d = extend(b, 4)
+		order.PutUint32(d, math.Float32bits(v))
+	case int64:
+		b, d = extend(b, 8)
+		order.PutUint64(d, uint64(v))
+	case uint64:
+		b, d = extend(b, 8)
+		order.PutUint64(d, v)
+	case float64:
+		b, d = extend(b, 8)
+		order.PutUint64(d, math.Float64bits(v))
+	case complex64:
+		b, d = extend(b, 8)
+		order.PutUint32(d, math.Float32bits(real(v)))
+		order.PutUint32(d[4:], math.Float32bits(imag(v)))
+	case complex128:
+		b, d = extend(b, 16)
+		order.PutUint64(d, math.Float64bits(real(v)))
+		order.PutUint64(d[8:], math.Float64bits(imag(v)))
+	case *big.Float:
+		b = appendEXT(b, v, order)
+	case LVTimestamp:
+		b = appendTimestamp(b, v, order)
+	case time.Time:
+		b = appendTimestamp(b, LVTimestampFromTime(v), order)
+	case string:
+		b = appendString(b, v, order)
+	}
+	return b, nil
+}
+
+// Appends a string, preceded by its length
+func appendString(b []byte, s string, order binary.ByteOrder) []byte {
+	b, d := extend(b, 4)
+	order.PutUint32(d, uint32(len(s)))
+	return append(b, s...)
+}
+
+// Appends a Timestamp, Fractions first in Little Endian Segments
+func appendTimestamp(b []byte, t LVTimestamp, order binary.ByteOrder) []byte {
+	b, d := extend(b, 16)
+	if order == binary.BigEndian {
+		order.PutUint64(d[0:8], uint64(t.Seconds))
+		order.PutUint64(d[8:16], t.Fractions)
+	} else {
+		order.PutUint64(d[0:8], t.Fractions)
+		order.PutUint64(d[8:16], uint64(t.Seconds))
+	}
+	return b
+}
+
+// Appends an EXT value, see extExponentBias for the formats
+// nil is written as NaN, values too large for the format as infinity
+// and values too small as 0
+func appendEXT(b []byte, value *big.Float, order binary.ByteOrder) []byte {
+	b, d := extend(b, 16)
+	for i := range d {
+		d[i] = 0
+	}
+
+	// Bits of the Mantissa, including the integer bit
+	mantissaBits := 64
+	if order == binary.BigEndian {
+		mantissaBits = 113
+	}
+
+	negative := false
+	exponent := 0
+	mantissa := new(big.Int)
+	switch {
+	case value == nil:
+		// Quiet NaN
+		exponent = 0x7FFF
+		mantissa.SetBit(mantissa, mantissaBits-2, 1)
+		if order != binary.BigEndian {
+			mantissa.SetBit(mantissa, mantissaBits-1, 1)
+		}
+	case value.IsInf():
+		negative = value.Signbit()
+		exponent = 0x7FFF
+		if order != binary.BigEndian {
+			mantissa.SetBit(mantissa, mantissaBits-1, 1)
+		}
+	case value.Sign() == 0:
+		negative = value.Signbit()
+	default:
+		negative = value.Signbit()
+		rounded := new(big.Float).SetPrec(uint(mantissaBits)).SetMode(big.ToNearestEven).Abs(value)
+		fraction := new(big.Float)
+		exp := rounded.MantExp(fraction)
+		// rounded = fraction * 2^exp, with 0.5 <= fraction < 1
+		fraction.SetMantExp(fraction, mantissaBits)
+		fraction.Int(mantissa)
+		exponent = exp - 1 + extExponentBias
+		if exponent >= 0x7FFF {
+			exponent = 0x7FFF
+			mantissa.SetInt64(0)
+			if order != binary.BigEndian {
+				mantissa.SetBit(mantissa, mantissaBits-1, 1)
+			}
+		} else if exponent <= 0 {
+			// Subnormal, with the exponent of the smallest normal value
+			shift := 1 - exponent
+			if shift > mantissaBits {
+				shift = mantissaBits
+			}
+			mantissa.Rsh(mantissa, uint(shift))
+			exponent = 0
+		}
+	}
+
+	if order == binary.BigEndian {
+		// The integer bit is implicit
+		mantissa.SetBit(mantissa, mantissaBits-1, 0)
+		lo := new(big.Int).And(mantissa, new(big.Int).SetUint64(math.MaxUint64)).Uint64()
+		hi := new(big.Int).Rsh(mantissa, 64).Uint64() | uint64(exponent)<<48
+		if negative {
+			hi |= 1 << 63
+		}
+		order.PutUint64(d[0:8], hi)
+		order.PutUint64(d[8:16], lo)
+		return b
+	}
+
+	signExponent := uint16(exponent)
+	if negative {
+		signExponent |= 1 << 15
+	}
+	order.PutUint64(d[0:8], mantissa.Uint64())
+	order.PutUint16(d[8:10], signExponent)
+	return b
+}
+
+// Appends a Segment's Lead In, in the Byte Order set by the ToC Mask
+func appendLeadIn(b []byte, tocMask uint32, metaLength uint64, dataLength uint64) []byte {
+	order := byteOrder(tocMask)
+	b, d := extend(b, leadInSize)
+	copy(d, segmentTag)
+	binary.LittleEndian.PutUint32(d[4:8], tocMask)
+	order.PutUint32(d[8:12], Version2)
+	order.PutUint64(d[12:20], metaLength+dataLength)
+	order.PutUint64(d[20:28], metaLength)
+	return b
+}
+
+// Appends the Raw Data Index of numValues values of the Data Type,
+// size is the Bytes of Raw Data, only written for Strings
+func appendRawDataIndex(b []byte, dataType TdsDataType, numValues uint64, size uint64, order binary.ByteOrder) []byte {
+	length := 20
+	if dataType == String {
+		length = 28
+	}
+	b, d := extend(b, length)
+	order.PutUint32(d[0:4], uint32(length))
+	order.PutUint32(d[4:8], uint32(dataType))
+	order.PutUint32(d[8:12], 1)
+	order.PutUint64(d[12:20], numValues)
+	if dataType == String {
+		order.PutUint64(d[20:28], size)
+	}
+	return b
+}
+
+// Appends an Object to a Segment's Metadata
+// rawDataIndex is NoRawDataValue, MatchesPreviousValue, or a Raw Data
+// Index from appendRawDataIndex
+func appendObject(b []byte, path string, rawDataIndex []byte, properties []Property, order binary.ByteOrder) ([]byte, error) {
+	b = appendString(b, path, order)
+	b = append(b, rawDataIndex...)
+
+	b, d := extend(b, 4)
+	order.PutUint32(d, uint32(len(properties)))
+	for _, property := range properties {
+		var err error
+		b = appendString(b, property.Name, order)
+		b, err = appendProperty(b, property, order)
+		if err != nil {
+			return b, err
+		}
+	}
+	return b, nil
+}
+
+// Appends a Property's Data Type and value
+func appendProperty(b []byte, property Property, order binary.ByteOrder) ([]byte, error) {
+	b, d := extend(b, 4)
+	order.PutUint32(d, uint32(property.DataType))
+	b, err := appendValue(b, property.DataType, property.value, order)
+	if err != nil {
+		return b, fmt.Errorf("property %s: %w", property.Name, err)
+	}
+	return b, nil
+}
+
+// Properties of an Object in the order they are written, sorted by Name
+func sortedProperties(properties map[string]Property) []Property {
+	sorted := make(Properties, 0, len(properties))
+	for _, property := range properties {
+		sorted = append(sorted, property)
+	}
+	sort.Sort(sorted)
+	return sorted
+}
@@ -0,0 +1,76 @@
+package tdms
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Errors that can occur while Reading or Writing a TDMS File
+// Test for them with errors.Is
+//
+// Errors reading or writing a File's bytes are wrapped in an *Error,
+// with the Offset and Object Path
+// Errors finding Groups and Channels, converting Properties, Scaling
+// and parsing Object Paths are wrapped by fmt.Errorf
+var (
+	ErrNotTDMS             = errors.New("not a TDMS segment")
+	ErrTruncatedSegment    = errors.New("truncated segment")
+	ErrUnsupportedDataType = errors.New("unsupported data type")
+	ErrInvalidDimension    = errors.New("array dimension is not 1")
+	ErrUnknownObject       = errors.New("raw data index matches previous, though object has not been seen before")
+	ErrInvalidChunkSize    = errors.New("data size is not a multiple of chunk size")
+	ErrNoChannelData       = errors.New("channel has no raw data in segment")
+	ErrUnsupportedScaling  = errors.New("unsupported scaling")
+	ErrPropertyType        = errors.New("property value is not of the requested type")
+	ErrGroupNotFound       = errors.New("group not found")
+	ErrChannelNotFound     = errors.New("channel not found")
+	ErrInvalidPath         = errors.New("invalid object path")
+	ErrOutOfRange          = errors.New("sample range is out of bounds")
+	ErrStaleIndex          = errors.New("index file does not match data file")
+	ErrInvalidVersion      = errors.New("unsupported TDMS version")
+	ErrMetadataLength      = errors.New("metadata length does not match lead-in")
+	ErrDuplicateObject     = errors.New("object is written more than once in a segment")
+)
+
+// Error describes where in a TDMS File a read failed
+// Includes:
+// - Operation that failed
+// - Byte Offset in the File
+// - Object Path, if known
+type Error struct {
+	Op     string
+	Offset int64
+	Path   string
+	Err    error
+}
+
+func (e *Error) Error() string {
+	if e.Path != "" {
+		return fmt.Sprintf("tdms: %s at byte %d (%s): %v", e.Op, e.Offset, e.Path, e.Err)
+	}
+	return fmt.Sprintf("tdms: %s at byte %d: %v", e.Op, e.Offset, e.Err)
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// Creates an *Error, io.EOF and io.ErrUnexpectedEOF are reported as ErrTruncatedSegment
+func newError(op string, offset int64, err error) error {
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		err = ErrTruncatedSegment
+	}
+	return &Error{
+		Op:     op,
+		Offset: offset,
+		Err:    err,
+	}
+}
+
+// Adds an Object Path to an *Error that does not have one yet
+func withPath(err error, path string) error {
+	var tdmsErr *Error
+	if errors.As(err, &tdmsErr) && tdmsErr.Path == "" {
+		tdmsErr.Path = path
+	}
+	return err
+}
@@ -0,0 +1,103 @@
+package tdms
+
+import (
+	"encoding/binary"
+	"math"
+	"math/big"
+)
+
+// EXT values take 16 Bytes in a TDMS File
+//
+// Little Endian Segments hold an 80-bit x87 extended float
+// in the first 10 Bytes:
+// - 64-bit Mantissa, with an explicit integer bit
+// - 15-bit Exponent, biased by 16383
+// - Sign Bit
+//
+// Big Endian Segments hold a 128-bit IEEE 754 quadruple float:
+// - Sign Bit
+// - 15-bit Exponent, biased by 16383
+// - 112-bit Mantissa, with an implicit integer bit
+const extExponentBias = 16383
+
+// Decodes a single EXT value
+// NaN can not be held by a big.Float, so is returned as nil
+func decodeEXT(b []byte, order binary.ByteOrder) *big.Float {
+	var negative bool
+	var exponent int
+	mantissa := new(big.Int)
+	var mantissaBits int
+
+	if order == binary.BigEndian {
+		hi := order.Uint64(b[0:8])
+		lo := order.Uint64(b[8:16])
+		negative = hi>>63 == 1
+		exponent = int(hi>>48) & 0x7FFF
+		mantissa.SetUint64(hi & (1<<48 - 1))
+		mantissa.Lsh(mantissa, 64)
+		mantissa.Or(mantissa, new(big.Int).SetUint64(lo))
+		mantissaBits = 112
+		if exponent == 0x7FFF {
+			if mantissa.Sign() != 0 {
+				return nil
+			}
+			return new(big.Float).SetInf(negative)
+		}
+		if exponent != 0 {
+			mantissa.SetBit(mantissa, 112, 1)
+		}
+	} else {
+		signExponent := order.Uint16(b[8:10])
+		negative = signExponent>>15 == 1
+		exponent = int(signExponent & 0x7FFF)
+		mantissa.SetUint64(order.Uint64(b[0:8]))
+		mantissaBits = 63
+		if exponent == 0x7FFF {
+			if mantissa.Uint64()<<1 != 0 {
+				return nil
+			}
+			return new(big.Float).SetInf(negative)
+		}
+	}
+
+	// Subnormal values have the exponent of the smallest normal value
+	if exponent == 0 {
+		exponent = 1
+	}
+
+	value := new(big.Float).SetPrec(uint(mantissaBits + 1)).SetInt(mantissa)
+	value.SetMantExp(value, exponent-extExponentBias-mantissaBits)
+	if negative {
+		value.Neg(value)
+	}
+	return value
+}
+
+// Decodes Raw Bytes into a []*big.Float
+// NaN values are nil
+func decodeEXTBigArray(b []byte, order binary.ByteOrder) []*big.Float {
+	vals := make([]*big.Float, len(b)/16)
+	for i := range vals {
+		vals[i] = decodeEXT(b[i*16:i*16+16], order)
+	}
+	return vals
+}
+
+// Decodes Raw Bytes into a []float64
+// Values are rounded to the nearest float64
+func decodeEXTArray(b []byte, order binary.ByteOrder) []float64 {
+	vals := make([]float64, len(b)/16)
+	for i := range vals {
+		vals[i] = extFloat64(decodeEXT(b[i*16:i*16+16], order))
+	}
+	return vals
+}
+
+// Rounds an EXT value to the nearest float64
+func extFloat64(value *big.Float) float64 {
+	if value == nil {
+		return math.NaN()
+	}
+	f, _ := value.Float64()
+	return f
+}
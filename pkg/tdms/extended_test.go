@@ -0,0 +1,88 @@
+package tdms
+
+import (
+	"encoding/binary"
+	"math"
+	"math/big"
+	"testing"
+)
+
+// An 80-bit EXT value, padded to 16 Bytes
+func ext80(mantissa uint64, signExponent uint16) []byte {
+	b := make([]byte, 16)
+	binary.LittleEndian.PutUint64(b[0:8], mantissa)
+	binary.LittleEndian.PutUint16(b[8:10], signExponent)
+	return b
+}
+
+// A 128-bit EXT value
+func ext128(hi uint64, lo uint64) []byte {
+	b := make([]byte, 16)
+	binary.BigEndian.PutUint64(b[0:8], hi)
+	binary.BigEndian.PutUint64(b[8:16], lo)
+	return b
+}
+
+// 2^exp, exactly
+func pow2(exp int) *big.Float {
+	return new(big.Float).SetMantExp(big.NewFloat(1), exp)
+}
+
+// a + b, exactly
+func exactSum(a *big.Float, b *big.Float) *big.Float {
+	return new(big.Float).SetPrec(200).Add(a, b)
+}
+
+func TestDecodeEXT(t *testing.T) {
+	cases := []struct {
+		name  string
+		order binary.ByteOrder
+		b     []byte
+		// nil for NaN
+		want *big.Float
+	}{
+		{"80-bit One", binary.LittleEndian, ext80(1<<63, 0x3FFF), big.NewFloat(1)},
+		{"80-bit Negative", binary.LittleEndian, ext80(0xA000000000000000, 0xC000), big.NewFloat(-2.5)},
+		{"80-bit Zero", binary.LittleEndian, ext80(0, 0), big.NewFloat(0)},
+		{"80-bit Beyond float64", binary.LittleEndian, ext80(1<<63|1, 0x3FFF), exactSum(big.NewFloat(1), pow2(-63))},
+		{"80-bit Largest Exponent", binary.LittleEndian, ext80(1<<63, 0x7FFE), pow2(16383)},
+		{"80-bit Smallest Subnormal", binary.LittleEndian, ext80(1, 0), pow2(1 - 16383 - 63)},
+		{"80-bit Infinity", binary.LittleEndian, ext80(1<<63, 0x7FFF), new(big.Float).SetInf(false)},
+		{"80-bit Negative Infinity", binary.LittleEndian, ext80(1<<63, 0xFFFF), new(big.Float).SetInf(true)},
+		{"80-bit NaN", binary.LittleEndian, ext80(0xC000000000000000, 0x7FFF), nil},
+		{"128-bit One", binary.BigEndian, ext128(0x3FFF000000000000, 0), big.NewFloat(1)},
+		{"128-bit Negative", binary.BigEndian, ext128(0xC000400000000000, 0), big.NewFloat(-2.5)},
+		{"128-bit Zero", binary.BigEndian, ext128(0, 0), big.NewFloat(0)},
+		{"128-bit Beyond float64", binary.BigEndian, ext128(0x3FFF000000000000, 1), exactSum(big.NewFloat(1), pow2(-112))},
+		{"128-bit Largest Exponent", binary.BigEndian, ext128(0x7FFE000000000000, 0), pow2(16383)},
+		{"128-bit Smallest Subnormal", binary.BigEndian, ext128(0, 1), pow2(1 - 16383 - 112)},
+		{"128-bit Infinity", binary.BigEndian, ext128(0x7FFF000000000000, 0), new(big.Float).SetInf(false)},
+		{"128-bit Negative Infinity", binary.BigEndian, ext128(0xFFFF000000000000, 0), new(big.Float).SetInf(true)},
+		{"128-bit NaN", binary.BigEndian, ext128(0x7FFF800000000000, 0), nil},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := decodeEXT(c.b, c.order)
+			if !equalEXT(got, c.want) {
+				t.Fatalf("got %v, want %v", got, c.want)
+			}
+			// Values the format can hold are written back unchanged
+			if b := appendEXT(nil, got, c.order); string(b) != string(c.b) {
+				t.Errorf("appendEXT: got %x, want %x", b, c.b)
+			}
+		})
+	}
+}
+
+func TestDecodeEXTArray(t *testing.T) {
+	var b []byte
+	b = append(b, ext80(1<<63|1, 0x3FFF)...)
+	b = append(b, ext80(1<<63, 0x7FFE)...)
+	b = append(b, ext80(0xC000000000000000, 0x7FFF)...)
+
+	got := decodeEXTArray(b, binary.LittleEndian)
+	// Rounded to the nearest float64, or past it to infinity
+	if len(got) != 3 || got[0] != 1 || !math.IsInf(got[1], 1) || !math.IsNaN(got[2]) {
+		t.Errorf("got %v, want [1 +Inf NaN]", got)
+	}
+}
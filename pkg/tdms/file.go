@@ -0,0 +1,338 @@
+package tdms
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// A TDMS File read from a Source
+// Holds the parsed Segments and latest Properties of every Object,
+// Raw Data is only read when a Channel's Data is requested
+type File struct {
+	src        Source
+	closer     io.Closer
+	segments   []Segment
+	properties map[string]map[string]Property
+	groups     []*Group
+}
+
+// A Group of Channels in a TDMS File
+type Group struct {
+	file     *File
+	name     string
+	path     string
+	channels []*Channel
+}
+
+// A Channel of a TDMS File
+type Channel struct {
+	file  *File
+	group *Group
+	name  string
+	path  string
+	// Built on first use, see SampleIndex
+	index *SampleIndex
+}
+
+// Suffix of the Index File LabVIEW writes next to a TDMS File
+const IndexFileSuffix = "_index"
+
+// Opens and reads the TDMS File at the given path
+// The Metadata is read from the .tdms_index File if there is one
+// that matches the File
+//
+// The File must be Closed once finished with
+func Open(name string) (*File, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	src, err := NewFileSource(file)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return newClosingFile(name, src, file)
+}
+
+// Opens and reads the TDMS File at the given path using a memory mapping
+// Values are decoded straight from the mapped pages, without copying
+// Falls back to regular reads where memory mapping is not supported
+//
+// The File must be Closed once finished with, and the File's Data
+// must not be used once Closed
+func OpenMmap(name string) (*File, error) {
+	src, closer, err := openMmapSource(name)
+	if err != nil {
+		return nil, err
+	}
+	return newClosingFile(name, src, closer)
+}
+
+// Reads the File name from a Source, closing closer if it can not be read
+func newClosingFile(name string, src Source, closer io.Closer) (*File, error) {
+	f, err := readFileWithIndex(name, src)
+	if err != nil {
+		closer.Close()
+		return nil, err
+	}
+	f.closer = closer
+	return f, nil
+}
+
+// Reads a File, using the Index File next to it if present
+func readFileWithIndex(name string, src Source) (*File, error) {
+	indexFile, err := os.Open(name + IndexFileSuffix)
+	if err != nil {
+		return NewFile(src)
+	}
+	defer indexFile.Close()
+
+	index, err := NewFileSource(indexFile)
+	if err != nil {
+		return NewFile(src)
+	}
+	return NewIndexedFile(src, index)
+}
+
+// Reads the Segments of a TDMS File from a Source
+func NewFile(src Source) (*File, error) {
+	segments, properties, err := ReadAllSegments(src)
+	if err != nil {
+		return nil, err
+	}
+	return newFile(src, segments, properties), nil
+}
+
+// Reads the Segments of a TDMS File from its Index File
+// Only the Index is read, Raw Data is still read from src
+//
+// Falls back to reading src if the Index can not be read, or is stale
+func NewIndexedFile(src Source, index Source) (*File, error) {
+	segments, properties, err := ReadIndexSegments(index, src.Size())
+	if err != nil {
+		log.Debugf("Unable to use index file, reading data file: %v", err)
+		return NewFile(src)
+	}
+	return newFile(src, segments, properties), nil
+}
+
+// Creates the Groups and Channels of a File from its Segments
+func newFile(src Source, segments []Segment, properties map[string]map[string]Property) *File {
+	f := &File{
+		src:        src,
+		segments:   segments,
+		properties: properties,
+	}
+
+	// Groups and Channels are kept in the order they were first written
+	groupsByPath := make(map[string]*Group)
+	for _, path := range ReadAllUniqueTDMSObjects(segments) {
+		// Paths that can not be parsed are left out, Verify reports them
+		names, _ := ParsePath(path)
+		switch len(names) {
+		case 1:
+			if _, present := groupsByPath[path]; !present {
+				groupsByPath[path] = f.addGroup(names[0], path)
+			}
+		case 2:
+			groupPath := GroupPath(names[0])
+			group, present := groupsByPath[groupPath]
+			if !present {
+				// Channels can be written without their Group
+				group = f.addGroup(names[0], groupPath)
+				groupsByPath[groupPath] = group
+			}
+			group.channels = append(group.channels, &Channel{f, group, names[1], path, nil})
+		}
+	}
+
+	return f
+}
+
+func (f *File) addGroup(name string, path string) *Group {
+	group := &Group{f, name, path, nil}
+	f.groups = append(f.groups, group)
+	return group
+}
+
+// Closes the underlying os.File if the File was Opened by name
+func (f *File) Close() error {
+	if f.closer == nil {
+		return nil
+	}
+	return f.closer.Close()
+}
+
+// Source the File is read from
+func (f *File) Source() Source { return f.src }
+
+// Segments of the File in order
+func (f *File) Segments() []Segment { return f.segments }
+
+// Properties of the File Object
+func (f *File) Properties() map[string]Property { return f.objectProperties(rootPath) }
+
+// Groups of the File in the order they were written
+func (f *File) Groups() []*Group { return f.groups }
+
+// Finds a Group by name
+func (f *File) Group(name string) (*Group, error) {
+	for _, group := range f.groups {
+		if group.name == name {
+			return group, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrGroupNotFound, name)
+}
+
+// Latest Properties of an Object, an empty map if it has none
+func (f *File) objectProperties(path string) map[string]Property {
+	if properties, present := f.properties[path]; present {
+		return properties
+	}
+	return map[string]Property{}
+}
+
+// Name of the Group
+func (g *Group) Name() string { return g.name }
+
+// Object Path of the Group, e.g. /'Group'
+func (g *Group) Path() string { return g.path }
+
+// Properties of the Group
+func (g *Group) Properties() map[string]Property { return g.file.objectProperties(g.path) }
+
+// Channels of the Group in the order they were written
+func (g *Group) Channels() []*Channel { return g.channels }
+
+// Finds a Channel of the Group by name
+func (g *Group) Channel(name string) (*Channel, error) {
+	for _, channel := range g.channels {
+		if channel.name == name {
+			return channel, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s in group %s", ErrChannelNotFound, name, g.name)
+}
+
+// Name of the Channel
+func (c *Channel) Name() string { return c.name }
+
+// Group the Channel belongs to
+func (c *Channel) Group() *Group { return c.group }
+
+// Object Path of the Channel, e.g. /'Group'/'Channel'
+func (c *Channel) Path() string { return c.path }
+
+// Properties of the Channel
+func (c *Channel) Properties() map[string]Property { return c.file.objectProperties(c.path) }
+
+// Data Type of the Channel's Raw Data
+// Void if the Channel has no Raw Data
+func (c *Channel) DataType() TdsDataType {
+	dataType := Void
+	for _, segment := range c.file.segments {
+		if obj, present := segment.Objects[c.path]; present && obj.HasRawData() {
+			dataType = obj.RawDataIndex.DataType
+		}
+	}
+	return dataType
+}
+
+// Total number of values of the Channel across all Segments
+func (c *Channel) NumValues() uint64 {
+	total := uint64(0)
+	for _, segment := range c.file.segments {
+		if obj, present := segment.Objects[c.path]; present && obj.HasRawData() {
+			total += segmentNumValues(segment, c.path)
+		}
+	}
+	return total
+}
+
+// Reads all values of the Channel
+// DAQmx Channels are scaled by their NI_Scale Properties
+//
+// Returns a slice of the Channel's Data Type, see ReadSegmentChannelData
+func (c *Channel) Data() (interface{}, error) {
+	total := c.NumValues()
+	var data reflect.Value
+	for _, segment := range c.file.segments {
+		values, err := c.readSegment(segment)
+		if err != nil {
+			return nil, err
+		}
+		if values == nil {
+			continue
+		}
+
+		data, err = appendValues(data, values, total)
+		if err != nil {
+			return nil, &Error{"read channel data", int64(segment.DataPos), c.path, err}
+		}
+	}
+
+	if !data.IsValid() {
+		return nil, &Error{"read channel data", 0, c.path, ErrNoChannelData}
+	}
+	return data.Interface(), nil
+}
+
+// Reads all values of a numeric Channel as float64
+// Booleans are converted to 0 or 1, Complex values to their magnitude
+func (c *Channel) Float64() ([]float64, error) {
+	data := make([]float64, 0, c.NumValues())
+	for _, segment := range c.file.segments {
+		values, err := c.readSegment(segment)
+		if err != nil {
+			return nil, err
+		}
+		if values == nil {
+			continue
+		}
+
+		converted, ok := toFloat64Slice(values)
+		if !ok {
+			err := fmt.Errorf("%w: 0x%X is not numeric", ErrUnsupportedDataType, c.DataType())
+			return nil, &Error{"read channel data", int64(segment.DataPos), c.path, err}
+		}
+		data = append(data, converted...)
+	}
+	return data, nil
+}
+
+// Reads the Channel's values in a single Segment
+// Returns nil if the Channel has no Raw Data in the Segment
+func (c *Channel) readSegment(segment Segment) (interface{}, error) {
+	obj, present := segment.Objects[c.path]
+	if !present || !obj.HasRawData() {
+		return nil, nil
+	}
+	if obj.RawDataIndex.DAQmx != nil {
+		return ReadSegmentDAQmxData(c.file.src, segment, c.path, c.Properties())
+	}
+	return ReadSegmentChannelData(c.file.src, segment, c.path)
+}
+
+// Appends a slice of values to a slice of the same type
+// data may be the zero Value, in which case a slice with room for
+// total values is created, so appending does not grow it
+func appendValues(data reflect.Value, values interface{}, total uint64) (reflect.Value, error) {
+	v := reflect.ValueOf(values)
+	if !data.IsValid() {
+		if uint64(v.Len()) >= total {
+			return v, nil
+		}
+		data = reflect.MakeSlice(v.Type(), 0, int(total))
+	}
+	if data.Type() != v.Type() {
+		err := fmt.Errorf("%w: channel data changes from %s to %s", ErrUnsupportedDataType, data.Type(), v.Type())
+		return data, err
+	}
+	return reflect.AppendSlice(data, v), nil
+}
@@ -0,0 +1,189 @@
+package tdms
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Where a Channel's values are in the File
+// Used to read a range of values without reading the values before it
+type SampleIndex struct {
+	Path    string
+	Entries []SampleIndexEntry
+	// Total number of values of the Channel
+	NumValues uint64
+}
+
+// A Segment containing values of the Channel
+// Every Chunk of a Segment holds the same number of values
+type SampleIndexEntry struct {
+	// Index of the Segment in the File
+	Segment int
+	// Index of the Segment's first value in the Channel
+	Start          uint64
+	ValuesPerChunk uint64
+	NumChunks      uint64
+	// Byte Offset of the Channel's first value in the File
+	DataOffset uint64
+	// Bytes from one Chunk to the next
+	ChunkSize uint64
+
+	layout chunkLayout
+	// Index of the Entry's first Chunk in the Segment
+	firstChunk uint64
+}
+
+// Builds the SampleIndex of a Channel from the parsed Segments
+// The SampleIndex is built once, then reused
+func (c *Channel) SampleIndex() (*SampleIndex, error) {
+	if c.index != nil {
+		return c.index, nil
+	}
+
+	index := &SampleIndex{Path: c.path}
+	for i, segment := range c.file.segments {
+		obj, present := segment.Objects[c.path]
+		if !present || !obj.HasRawData() || segment.NumChunks == 0 {
+			continue
+		}
+
+		layout, err := channelLayout(segment, c.path)
+		if err != nil {
+			return nil, err
+		}
+
+		// The final Chunk of a truncated Segment holds fewer values,
+		// so has an Entry of its own
+		numChunks := segment.NumChunks
+		if segment.FinalChunkLengthOverride != 0 {
+			numChunks--
+		}
+		if numChunks > 0 {
+			index.Entries = append(index.Entries, SampleIndexEntry{
+				i,
+				index.NumValues,
+				layout.numValues,
+				numChunks,
+				segment.DataPos + layout.offset,
+				layout.chunkSize,
+				layout,
+				0,
+			})
+			index.NumValues += layout.numValues * numChunks
+		}
+		if numChunks == segment.NumChunks {
+			continue
+		}
+		finalValues := chunkValues(segment, c.path, layout, numChunks)
+		if finalValues > 0 {
+			index.Entries = append(index.Entries, SampleIndexEntry{
+				i,
+				index.NumValues,
+				finalValues,
+				1,
+				segment.DataPos + numChunks*layout.chunkSize + layout.offset,
+				layout.chunkSize,
+				layout,
+				numChunks,
+			})
+			index.NumValues += finalValues
+		}
+	}
+
+	c.index = index
+	return index, nil
+}
+
+// Finds the Entry, Chunk and position in the Chunk of the n'th value
+func (s *SampleIndex) Locate(n uint64) (int, uint64, uint64, error) {
+	if n >= s.NumValues {
+		err := fmt.Errorf("%w: value %d of %d", ErrOutOfRange, n, s.NumValues)
+		return 0, 0, 0, &Error{"locate value", 0, s.Path, err}
+	}
+
+	// First Entry starting after n, n is in the one before
+	entry := sort.Search(len(s.Entries), func(i int) bool {
+		return s.Entries[i].Start > n
+	}) - 1
+
+	offset := n - s.Entries[entry].Start
+	valuesPerChunk := s.Entries[entry].ValuesPerChunk
+	return entry, offset / valuesPerChunk, offset % valuesPerChunk, nil
+}
+
+// Reads count values of the Channel, starting at the start'th value
+// Only the Chunks holding the requested values are read
+// DAQmx Channels are scaled by their NI_Scale Properties
+//
+// Returns a slice of the Channel's Data Type, see Channel.Data
+func (c *Channel) ReadRange(start uint64, count uint64) (interface{}, error) {
+	index, err := c.SampleIndex()
+	if err != nil {
+		return nil, err
+	}
+	if len(index.Entries) == 0 {
+		return nil, &Error{"read channel range", 0, c.path, ErrNoChannelData}
+	}
+	if start > index.NumValues || count > index.NumValues-start {
+		err := fmt.Errorf("%w: values %d to %d of %d", ErrOutOfRange, start, start+count, index.NumValues)
+		return nil, &Error{"read channel range", 0, c.path, err}
+	}
+
+	// An empty range still returns a slice of the Channel's type
+	entry, chunk, offset := 0, uint64(0), uint64(0)
+	if count > 0 {
+		entry, chunk, offset, err = index.Locate(start)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var scaling Scaling
+	var data reflect.Value
+	remaining := count
+	for {
+		e := index.Entries[entry]
+		segment := c.file.segments[e.Segment]
+
+		n := e.ValuesPerChunk - offset
+		if n > remaining {
+			n = remaining
+		}
+		values, err := readChunkValues(c.file.src, segment, c.path, e.layout, e.firstChunk+chunk, offset, n)
+		if err != nil {
+			return nil, err
+		}
+
+		raw, ok := values.([]float64)
+		if ok && segment.Objects[c.path].RawDataIndex.DAQmx != nil {
+			if scaling == nil {
+				scaling, err = ScalingFromProperties(c.Properties())
+				if err != nil {
+					return nil, withPath(err, c.path)
+				}
+			}
+			values = scaling.Scale(raw)
+		}
+
+		data, err = appendValues(data, values, count)
+		if err != nil {
+			return nil, &Error{"read channel range", int64(segment.DataPos), c.path, err}
+		}
+
+		remaining -= n
+		if remaining == 0 {
+			break
+		}
+
+		// Continue from the start of the next Chunk
+		offset = 0
+		chunk++
+		if chunk == e.NumChunks {
+			entry++
+			chunk = 0
+		}
+	}
+
+	return data.Interface(), nil
+}
@@ -0,0 +1,92 @@
+package tdms
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// Writes the Index of a TDMS File
+// Each Segment's Lead In and Metadata are copied from the File, with
+// the "TDSh" tag, leaving out the Raw Data
+func WriteIndex(src Source, w io.Writer) error {
+	segments, _, err := ReadAllSegments(src)
+	if err != nil {
+		return err
+	}
+
+	var buf []byte
+	for _, segment := range segments {
+		// Lead In and Metadata run from the start of the Segment to the Raw Data
+		size := segment.DataPos - segment.Position
+		buf = growBuffer(buf, int64(size))
+		err := readAt(src, buf, int64(segment.Position), "write index")
+		if err != nil {
+			return err
+		}
+		copy(buf, indexTag)
+
+		_, err = w.Write(buf)
+		if err != nil {
+			return newError("write index", int64(segment.Position), err)
+		}
+	}
+	return nil
+}
+
+// Writes the .tdms_index File of the TDMS File at the given path
+// An existing Index is only replaced once the new one is complete
+//
+// Returns the path of the Index File
+func WriteIndexFile(name string) (string, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	src, err := NewFileSource(file)
+	if err != nil {
+		return "", err
+	}
+
+	fi, err := file.Stat()
+	if err != nil {
+		return "", err
+	}
+
+	// The Index has the same permissions as the File
+	indexName := name + IndexFileSuffix
+	err = writeFileAtomic(indexName, fi.Mode().Perm(), func(w io.Writer) error {
+		return WriteIndex(src, w)
+	})
+	if err != nil {
+		return "", err
+	}
+	return indexName, nil
+}
+
+// Writes the File name with write, through a temporary File that is
+// renamed once complete, so an existing File is only replaced by a
+// complete one
+func writeFileAtomic(name string, perm os.FileMode, write func(w io.Writer) error) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(name), filepath.Base(name)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	err = tmp.Chmod(perm)
+	if err == nil {
+		err = write(tmp)
+	}
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), name)
+}
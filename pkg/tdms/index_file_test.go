@@ -0,0 +1,160 @@
+package tdms
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// A File of three Segments, with Channel values of 1 to 6
+func indexTestFile(t *testing.T) []byte {
+	path := ChannelPath("G", "C")
+	unit, _ := NewProperty("unit", "V")
+	return writeTestFile(t,
+		[]WriteObject{{GroupPath("G"), nil, nil}, {path, []Property{unit}, []float64{1, 2}}},
+		[]WriteObject{{path, nil, []float64{3, 4}}},
+		[]WriteObject{{path, nil, []float64{5, 6}}},
+	)
+}
+
+// The Index of the File b
+func writeTestIndex(t *testing.T, b []byte) []byte {
+	t.Helper()
+	var index bytes.Buffer
+	if err := WriteIndex(NewBytesSource(b), &index); err != nil {
+		t.Fatal(err)
+	}
+	return index.Bytes()
+}
+
+func TestNewIndexedFileStale(t *testing.T) {
+	data := indexTestFile(t)
+	index := writeTestIndex(t, data)
+	segments, _, err := ReadAllSegments(NewBytesSource(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Index of the first two Segments
+	short := index[:segments[0].DataPos-segments[0].Position+segments[1].DataPos-segments[1].Position]
+	// The first Segment's Lead In as in the File
+	untagged := append([]byte(segmentTag), index[4:]...)
+
+	cases := []struct {
+		name  string
+		index []byte
+		data  []byte
+		err   error
+		// Segments of the File
+		segments int
+	}{
+		{"Valid", index, data, nil, 3},
+		{"File Appended", short, data, ErrStaleIndex, 3},
+		{"File Truncated", index, data[:segments[2].Position], ErrStaleIndex, 2},
+		{"Not An Index", untagged, data, ErrNotTDMS, 3},
+		{"Empty", nil, data, ErrTruncatedSegment, 3},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, _, err := ReadIndexSegments(NewBytesSource(c.index), int64(len(c.data)))
+			if !errors.Is(err, c.err) {
+				t.Errorf("ReadIndexSegments: got %v, want %v", err, c.err)
+			}
+
+			// The Data File is only read for its Metadata if the Index is stale
+			counter := &countingReaderAt{bytes.NewReader(c.data), 0}
+			f, err := NewIndexedFile(NewSource(counter, int64(len(c.data))), NewBytesSource(c.index))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if read := counter.reads > 0; read != (c.err != nil) {
+				t.Errorf("got %d reads of the data file, want reads %v", counter.reads, c.err != nil)
+			}
+			if got := len(f.Segments()); got != c.segments {
+				t.Errorf("got %d segments, want %d", got, c.segments)
+			}
+			want := []float64{1, 2, 3, 4, 5, 6}[:2*c.segments]
+			if got := channelData(t, f, "G", "C"); !reflect.DeepEqual(got, want) {
+				t.Errorf("got %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestWriteIndex(t *testing.T) {
+	c := ChannelPath("G", "C")
+	files := []struct {
+		name string
+		data []byte
+	}{
+		{"Written", indexTestFile(t)},
+		{"Big Endian", writeTestFileMode(t, false, true, []WriteObject{{c, nil, []int16{1, 2}}}, []WriteObject{{c, nil, []string{"a", "bc"}}})},
+		{"Interleaved", writeTestFileMode(t, true, false, []WriteObject{{c, nil, []int32{1, 2}}, {ChannelPath("G", "D"), nil, []float64{3, 4}}})},
+		{"Metadata Reused", iteratorTestFile()},
+	}
+	for _, file := range files {
+		t.Run(file.name, func(t *testing.T) {
+			index := writeTestIndex(t, file.data)
+			fromIndex, indexProperties, err := ReadIndexSegments(NewBytesSource(index), int64(len(file.data)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			segments, properties, err := ReadAllSegments(NewBytesSource(file.data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(fromIndex, segments) {
+				t.Errorf("segments: got %+v, want %+v", fromIndex, segments)
+			}
+			if !reflect.DeepEqual(indexProperties, properties) {
+				t.Errorf("properties: got %v, want %v", indexProperties, properties)
+			}
+		})
+	}
+}
+
+func TestWriteIndexFile(t *testing.T) {
+	data := indexTestFile(t)
+	name := filepath.Join(t.TempDir(), "data.tdms")
+	if err := os.WriteFile(name, data, 0640); err != nil {
+		t.Fatal(err)
+	}
+
+	indexName, err := WriteIndexFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if indexName != name+IndexFileSuffix {
+		t.Errorf("got index %s, want %s", indexName, name+IndexFileSuffix)
+	}
+	index, err := os.ReadFile(indexName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := writeTestIndex(t, data); !bytes.Equal(index, want) {
+		t.Error("index file differs from WriteIndex")
+	}
+	if fi, err := os.Stat(indexName); err != nil || fi.Mode().Perm() != 0640 {
+		t.Errorf("got index mode %v, %v, want %v", fi.Mode().Perm(), err, os.FileMode(0640))
+	}
+	// Only the Index is left, without temporary Files
+	if entries, _ := os.ReadDir(filepath.Dir(name)); len(entries) != 2 {
+		t.Errorf("got %d files, want the file and its index", len(entries))
+	}
+
+	// Values appended after the Index was written are still read
+	appended := writeTestFile(t, []WriteObject{{ChannelPath("G", "C"), nil, []float64{7}}})
+	if err := os.WriteFile(name, append(data, appended...), 0640); err != nil {
+		t.Fatal(err)
+	}
+	f, err := Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if got, want := channelData(t, f, "G", "C"), []float64{1, 2, 3, 4, 5, 6, 7}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,117 @@
+package tdms
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func iteratorTestChannel(t *testing.T) *Channel {
+	t.Helper()
+	f, err := NewFile(NewBytesSource(iteratorTestFile()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, _ := f.Group("G")
+	c, err := g.Channel("A")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestSampleIndexLocate(t *testing.T) {
+	index, err := iteratorTestChannel(t).SampleIndex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if index.NumValues != 13 || len(index.Entries) != 4 {
+		t.Fatalf("got %d values in %d entries, want 13 in 4", index.NumValues, len(index.Entries))
+	}
+
+	cases := []struct {
+		n      uint64
+		entry  int
+		chunk  uint64
+		offset uint64
+	}{
+		{0, 0, 0, 0},
+		{1, 0, 0, 1},
+		{2, 0, 1, 0},
+		{5, 0, 2, 1},
+		{6, 1, 0, 0},
+		{9, 1, 1, 1},
+		{10, 2, 0, 0},
+		{11, 2, 0, 1},
+		// The final Chunk of the truncated Segment
+		{12, 3, 0, 0},
+	}
+	for _, c := range cases {
+		entry, chunk, offset, err := index.Locate(c.n)
+		if err != nil {
+			t.Errorf("%d: %v", c.n, err)
+			continue
+		}
+		if entry != c.entry || chunk != c.chunk || offset != c.offset {
+			t.Errorf("%d: got %d, %d, %d, want %d, %d, %d", c.n, entry, chunk, offset, c.entry, c.chunk, c.offset)
+		}
+	}
+
+	for _, n := range []uint64{13, math.MaxUint64} {
+		if _, _, _, err := index.Locate(n); !errors.Is(err, ErrOutOfRange) {
+			t.Errorf("%d: got %v, want ErrOutOfRange", n, err)
+		}
+	}
+}
+
+func TestChannelReadRange(t *testing.T) {
+	c := iteratorTestChannel(t)
+	all, err := c.Data()
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := all.([]int32)
+
+	// Every range, including empty ones at each end
+	for start := 0; start <= len(values); start++ {
+		for end := start; end <= len(values); end++ {
+			got, err := c.ReadRange(uint64(start), uint64(end-start))
+			if err != nil {
+				t.Fatalf("%d to %d: %v", start, end, err)
+			}
+			if want := values[start:end]; !reflect.DeepEqual(got, want) {
+				t.Errorf("%d to %d: got %#v, want %#v", start, end, got, want)
+			}
+		}
+	}
+
+	invalid := []struct {
+		start uint64
+		count uint64
+	}{
+		{14, 0},
+		{0, 14},
+		{13, 1},
+		{12, 2},
+		{1, math.MaxUint64},
+		{math.MaxUint64, 1},
+	}
+	for _, r := range invalid {
+		if _, err := c.ReadRange(r.start, r.count); !errors.Is(err, ErrOutOfRange) {
+			t.Errorf("%d from %d: got %v, want ErrOutOfRange", r.count, r.start, err)
+		}
+	}
+}
+
+func TestChannelReadRangeNoData(t *testing.T) {
+	f := readTestFile(t, writeTestFile(t, []WriteObject{{ChannelPath("G", "C"), nil, nil}}))
+	g, _ := f.Group("G")
+	c, err := g.Channel("C")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.ReadRange(0, 0); !errors.Is(err, ErrNoChannelData) {
+		t.Errorf("got %v, want ErrNoChannelData", err)
+	}
+}
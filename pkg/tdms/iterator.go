@@ -0,0 +1,141 @@
+package tdms
+
+import (
+	"io"
+)
+
+// Number of values in each Block when no maximum is given
+const DefaultBlockSize = 65536
+
+// A Block of consecutive values of a Channel, all from one Chunk
+type Block struct {
+	// Index of the Segment in the File
+	Segment int
+	// Index of the Chunk in the Segment
+	Chunk uint64
+	// Index of the first value in the Channel
+	Index uint64
+	// Seconds from wf_start_time to the first value
+	// 0 if the Channel has no wf_increment
+	TimeOffset float64
+	// Slice of the Channel's Data Type, see Channel.Data
+	Values interface{}
+}
+
+// Reads a Channel's values one Block at a time
+// Only a single Block is held in memory at once
+type ChannelIterator struct {
+	channel   *Channel
+	maxValues uint64
+	// Position of the next Block
+	segment int
+	chunk   uint64
+	start   uint64
+	index   uint64
+	// Waveform timing of the Channel
+	startOffset float64
+	increment   float64
+	// Scaling of DAQmx Channels, created with the first Block
+	scaling Scaling
+	err     error
+}
+
+// Creates an Iterator over the Channel's values
+// Blocks hold at most maxValues values, DefaultBlockSize if 0
+func (c *Channel) Iterator(maxValues uint64) *ChannelIterator {
+	if maxValues == 0 {
+		maxValues = DefaultBlockSize
+	}
+	it := &ChannelIterator{channel: c, maxValues: maxValues}
+
+	properties := c.Properties()
+	if prop, present := properties["wf_increment"]; present {
+		it.increment, _ = prop.AsFloat64()
+	}
+	if prop, present := properties["wf_start_offset"]; present {
+		it.startOffset, _ = prop.AsFloat64()
+	}
+	return it
+}
+
+// Reads the next Block of values
+// Returns io.EOF once every value has been read
+func (it *ChannelIterator) Next() (Block, error) {
+	if it.err != nil {
+		return Block{}, it.err
+	}
+
+	segments := it.channel.file.segments
+	path := it.channel.path
+	for ; it.segment < len(segments); it.segment, it.chunk, it.start = it.segment+1, 0, 0 {
+		segment := segments[it.segment]
+		obj, present := segment.Objects[path]
+		if !present || !obj.HasRawData() {
+			continue
+		}
+
+		for ; it.chunk < segment.NumChunks; it.chunk, it.start = it.chunk+1, 0 {
+			// The final Chunk of a truncated Segment may hold fewer values
+			numValues := obj.RawDataIndex.NumValues
+			if it.chunk == segment.NumChunks-1 && segment.FinalChunkLengthOverride != 0 {
+				layout, err := channelLayout(segment, path)
+				if err != nil {
+					it.err = err
+					return Block{}, err
+				}
+				numValues = chunkValues(segment, path, layout, it.chunk)
+			}
+			if it.start >= numValues {
+				continue
+			}
+
+			count := numValues - it.start
+			if count > it.maxValues {
+				count = it.maxValues
+			}
+			values, err := ReadChunkChannelData(it.channel.file.src, segment, path, it.chunk, it.start, count)
+			if err != nil {
+				it.err = err
+				return Block{}, err
+			}
+			if obj.RawDataIndex.DAQmx != nil {
+				values, err = it.scale(values)
+				if err != nil {
+					it.err = withPath(err, path)
+					return Block{}, it.err
+				}
+			}
+
+			block := Block{
+				it.segment,
+				it.chunk,
+				it.index,
+				it.startOffset + float64(it.index)*it.increment,
+				values,
+			}
+			it.start += count
+			it.index += count
+			return block, nil
+		}
+	}
+
+	it.err = io.EOF
+	return Block{}, it.err
+}
+
+// Applies the DAQmx Scaling of the Channel to a Block's values
+// Digital Line values are not scaled
+func (it *ChannelIterator) scale(values interface{}) (interface{}, error) {
+	raw, ok := values.([]float64)
+	if !ok {
+		return values, nil
+	}
+	if it.scaling == nil {
+		scaling, err := ScalingFromProperties(it.channel.Properties())
+		if err != nil {
+			return nil, err
+		}
+		it.scaling = scaling
+	}
+	return it.scaling.Scale(raw), nil
+}
@@ -0,0 +1,170 @@
+package tdms
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"reflect"
+	"testing"
+)
+
+// A Block expected from a ChannelIterator
+type testBlock struct {
+	segment int
+	chunk   uint64
+	index   uint64
+	count   int
+}
+
+// Builds a File where A has 2 values in each Chunk of:
+// - Segment 0, 3 Chunks
+// - Segment 1, 2 Chunks, reusing the Metadata
+// - Segment 3, a truncated Segment of 1 whole Chunk and a final Chunk
+// holding a single value of A
+// and is left out of Segment 2
+func iteratorTestFile() []byte {
+	a, b := ChannelPath("G", "A"), ChannelPath("G", "B")
+	next := int32(0)
+	chunkA := func(s *segmentBuilder, n int) {
+		for i := 0; i < n; i++ {
+			s.u32(uint32(next))
+			next++
+		}
+	}
+	chunkB := func(s *segmentBuilder) {
+		s.u64(math.Float64bits(-1))
+	}
+
+	data := appendTestSegment(nil, KTocMetaData|KTocNewObjList|KTocRawData, func(s *segmentBuilder) {
+		s.u32(2)
+		s.str(a)
+		s.index(Int32, 2)
+		s.u32(2)
+		s.str("wf_increment")
+		s.u32(uint32(DBL))
+		s.u64(math.Float64bits(0.5))
+		s.str("wf_start_offset")
+		s.u32(uint32(DBL))
+		s.u64(math.Float64bits(1))
+		s.str(b)
+		s.index(DBL, 1)
+		s.u32(0)
+	}, func(s *segmentBuilder) {
+		for i := 0; i < 3; i++ {
+			chunkA(s, 2)
+			chunkB(s)
+		}
+	})
+	data = appendTestSegment(data, KTocRawData, nil, func(s *segmentBuilder) {
+		for i := 0; i < 2; i++ {
+			chunkA(s, 2)
+			chunkB(s)
+		}
+	})
+	data = appendTestSegment(data, KTocMetaData|KTocNewObjList|KTocRawData, func(s *segmentBuilder) {
+		s.u32(1)
+		s.str(b)
+		s.index(DBL, 1)
+		s.u32(0)
+	}, chunkB)
+
+	// A is listed after B
+	truncated := len(data)
+	data = appendTestSegment(data, KTocMetaData|KTocRawData, func(s *segmentBuilder) {
+		s.u32(1)
+		s.str(a)
+		s.index(Int32, 2)
+		s.u32(0)
+	}, func(s *segmentBuilder) {
+		chunkB(s)
+		chunkA(s, 2)
+		chunkB(s)
+		chunkA(s, 1)
+	})
+	binary.LittleEndian.PutUint64(data[truncated+12:], incompleteSegLength)
+	return data
+}
+
+func TestChannelIterator(t *testing.T) {
+	f, err := NewFile(NewBytesSource(iteratorTestFile()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, _ := f.Group("G")
+	c, err := g.Channel("A")
+	if err != nil {
+		t.Fatal(err)
+	}
+	all, err := c.Data()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int32{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}; !reflect.DeepEqual(all, want) {
+		t.Fatalf("Data: got %v, want %v", all, want)
+	}
+
+	// Values of A in each Chunk
+	chunks := []testBlock{
+		{0, 0, 0, 2}, {0, 1, 0, 2}, {0, 2, 0, 2},
+		{1, 0, 0, 2}, {1, 1, 0, 2},
+		{3, 0, 0, 2}, {3, 1, 0, 1},
+	}
+	for _, maxValues := range []uint64{1, 2, 3, 0} {
+		var want []testBlock
+		index := uint64(0)
+		for _, chunk := range chunks {
+			for start := 0; start < chunk.count; start += int(maxValues) {
+				count := chunk.count - start
+				if maxValues != 0 && count > int(maxValues) {
+					count = int(maxValues)
+				}
+				want = append(want, testBlock{chunk.segment, chunk.chunk, index, count})
+				index += uint64(count)
+				if maxValues == 0 {
+					break
+				}
+			}
+		}
+
+		it := c.Iterator(maxValues)
+		var got []testBlock
+		var values []int32
+		for {
+			block, err := it.Next()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			blockValues := block.Values.([]int32)
+			got = append(got, testBlock{block.Segment, block.Chunk, block.Index, len(blockValues)})
+			values = append(values, blockValues...)
+			if want := 1 + float64(block.Index)*0.5; block.TimeOffset != want {
+				t.Errorf("max %d, block at %d: got time offset %g, want %g", maxValues, block.Index, block.TimeOffset, want)
+			}
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("max %d: got blocks %v, want %v", maxValues, got, want)
+		}
+		if !reflect.DeepEqual(values, all) {
+			t.Errorf("max %d: got values %v, want %v", maxValues, values, all)
+		}
+		if _, err := it.Next(); !errors.Is(err, io.EOF) {
+			t.Errorf("max %d: got %v after the last block, want io.EOF", maxValues, err)
+		}
+	}
+}
+
+func TestChannelIteratorEmpty(t *testing.T) {
+	f := readTestFile(t, writeTestFile(t, []WriteObject{{ChannelPath("G", "C"), nil, nil}}))
+	g, _ := f.Group("G")
+	c, err := g.Channel("C")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Iterator(0).Next(); !errors.Is(err, io.EOF) {
+		t.Errorf("got %v, want io.EOF", err)
+	}
+}
@@ -0,0 +1,56 @@
+package tdms
+
+import (
+	"io"
+	"os"
+	"syscall"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// A Source backed by a read only memory mapping of a File
+type mmapSource struct {
+	bytesSource
+}
+
+// Unmaps the File
+func (m *mmapSource) Close() error {
+	if m.bytesSource == nil {
+		return nil
+	}
+	err := syscall.Munmap(m.bytesSource)
+	m.bytesSource = nil
+	return err
+}
+
+// Memory maps a File as a Source, values are decoded straight
+// from the mapped pages
+// The mapping is kept once the os.File is closed
+//
+// Falls back to regular reads if the File can not be mapped
+// Returns a Closer that unmaps the File, or closes the os.File
+func openMmapSource(name string) (Source, io.Closer, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	fi, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, nil, newError("stat file", 0, err)
+	}
+
+	// Empty Files can not be mapped
+	size := fi.Size()
+	if size > 0 && int64(int(size)) == size {
+		data, err := syscall.Mmap(int(file.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_SHARED)
+		if err == nil {
+			file.Close()
+			src := &mmapSource{data}
+			return src, src, nil
+		}
+		log.Debugf("Unable to memory map %s, using regular reads: %v", name, err)
+	}
+
+	return NewSource(file, size), file, nil
+}
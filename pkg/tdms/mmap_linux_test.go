@@ -0,0 +1,76 @@
+package tdms
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestOpenMmap(t *testing.T) {
+	values := []float64{1, 2, 3}
+	name := filepath.Join(t.TempDir(), "mapped.tdms")
+	b := writeTestFile(t, []WriteObject{{ChannelPath("G", "C"), nil, values}})
+	if err := os.WriteFile(name, b, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := OpenMmap(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := f.Source().(*mmapSource); !ok {
+		t.Errorf("got source %T, want *mmapSource", f.Source())
+	}
+	if got := channelData(t, f, "G", "C"); !reflect.DeepEqual(got, values) {
+		t.Errorf("got %v, want %v", got, values)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	// Closing again leaves the unmapped File alone
+	if err := f.Close(); err != nil {
+		t.Errorf("second close: %v", err)
+	}
+}
+
+// Files that can not be mapped are read with regular reads
+func TestOpenMmapFallback(t *testing.T) {
+	dir := t.TempDir()
+	empty := filepath.Join(dir, "empty.tdms")
+	if err := os.WriteFile(empty, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name string
+		path string
+	}{
+		{"Empty", empty},
+		// Opens, but can not be mapped
+		{"Directory", dir},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			src, closer, err := openMmapSource(c.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer closer.Close()
+			if _, ok := src.(*mmapSource); ok {
+				t.Error("got a mapped source")
+			}
+			if _, ok := closer.(*os.File); !ok {
+				t.Errorf("got closer %T, want *os.File", closer)
+			}
+		})
+	}
+
+	if _, err := OpenMmap(empty); !errors.Is(err, ErrTruncatedSegment) {
+		t.Errorf("empty file: got %v, want ErrTruncatedSegment", err)
+	}
+	if _, err := OpenMmap(filepath.Join(dir, "missing.tdms")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("missing file: got %v, want os.ErrNotExist", err)
+	}
+}
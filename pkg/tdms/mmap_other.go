@@ -0,0 +1,26 @@
+//go:build !linux
+// +build !linux
+
+package tdms
+
+import (
+	"io"
+	"os"
+)
+
+// Memory mapping is only supported on Linux, Files are read with
+// regular reads
+//
+// Returns a Closer that closes the os.File
+func openMmapSource(name string) (Source, io.Closer, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	src, err := NewFileSource(file)
+	if err != nil {
+		file.Close()
+		return nil, nil, err
+	}
+	return src, file, nil
+}
@@ -0,0 +1,76 @@
+package tdms
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Object Paths name the File, a Group or a Channel
+// - File: /
+// - Group: /'Group'
+// - Channel: /'Group'/'Channel'
+//
+// Names are quoted, so may contain slashes
+// A quote within a name is escaped by doubling it:
+//
+//	/'Operator''s note'/'Flow (l/min)'
+const rootPath = "/"
+
+// Parses an Object Path into its Group and Channel names
+// Returns no names for the File, one for a Group and two for a Channel
+func ParsePath(path string) ([]string, error) {
+	if path == rootPath {
+		return nil, nil
+	}
+
+	var names []string
+	i := 0
+	for i < len(path) {
+		if path[i] != '/' || i+1 >= len(path) || path[i+1] != '\'' {
+			return nil, fmt.Errorf("%w: %q expected /' at %d", ErrInvalidPath, path, i)
+		}
+		i += 2
+
+		// Name runs until a quote that is not doubled
+		var name strings.Builder
+		closed := false
+		for i < len(path) {
+			if path[i] == '\'' {
+				if i+1 < len(path) && path[i+1] == '\'' {
+					name.WriteByte('\'')
+					i += 2
+					continue
+				}
+				i++
+				closed = true
+				break
+			}
+			name.WriteByte(path[i])
+			i++
+		}
+		if !closed {
+			return nil, fmt.Errorf("%w: %q has an unterminated name", ErrInvalidPath, path)
+		}
+		names = append(names, name.String())
+	}
+
+	if len(names) == 0 || len(names) > 2 {
+		return nil, fmt.Errorf("%w: %q has %d names", ErrInvalidPath, path, len(names))
+	}
+	return names, nil
+}
+
+// Object Path of a Group
+func GroupPath(group string) string {
+	return "/" + quoteName(group)
+}
+
+// Object Path of a Channel
+func ChannelPath(group string, channel string) string {
+	return GroupPath(group) + "/" + quoteName(channel)
+}
+
+// Quotes a Group or Channel name for an Object Path
+func quoteName(name string) string {
+	return "'" + strings.Replace(name, "'", "''", -1) + "'"
+}
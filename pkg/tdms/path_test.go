@@ -0,0 +1,122 @@
+package tdms
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParsePath(t *testing.T) {
+	cases := []struct {
+		path string
+		want []string
+	}{
+		{"/", nil},
+		{"/'Group'", []string{"Group"}},
+		{"/'Group'/'Channel'", []string{"Group", "Channel"}},
+		{"/'Operator''s note'/'Flow (l/min)'", []string{"Operator's note", "Flow (l/min)"}},
+		{"/'/'/'//'", []string{"/", "//"}},
+		{"/''''''", []string{"''"}},
+		{"/''/''", []string{"", ""}},
+		{"/'a''/''b'", []string{"a'/'b"}},
+	}
+	for _, c := range cases {
+		t.Run(c.path, func(t *testing.T) {
+			got, err := ParsePath(c.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, c.want) {
+				t.Errorf("got %q, want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestParsePathInvalid(t *testing.T) {
+	paths := []string{
+		"",
+		"Group",
+		"'Group'",
+		"/Group",
+		"//'Group'",
+		"/'Group",
+		"/'Group''",
+		"/'Group'/",
+		"/'Group'x",
+		"/'Group' /'Channel'",
+		"/'Group'/'Channel",
+		"/'Group'/'Channel'/'Extra'",
+	}
+	for _, path := range paths {
+		t.Run(path, func(t *testing.T) {
+			if names, err := ParsePath(path); !errors.Is(err, ErrInvalidPath) {
+				t.Errorf("got %q, %v, want ErrInvalidPath", names, err)
+			}
+		})
+	}
+}
+
+func TestPathRoundTrip(t *testing.T) {
+	names := []string{"", "plain", "it's", "''", "a/b", "/'/'", "end'"}
+	for _, group := range names {
+		for _, channel := range names {
+			if got, err := ParsePath(GroupPath(group)); err != nil || !reflect.DeepEqual(got, []string{group}) {
+				t.Errorf("GroupPath(%q): got %q, %v", group, got, err)
+			}
+			got, err := ParsePath(ChannelPath(group, channel))
+			if err != nil || !reflect.DeepEqual(got, []string{group, channel}) {
+				t.Errorf("ChannelPath(%q, %q): got %q, %v", group, channel, got, err)
+			}
+		}
+	}
+}
+
+// Objects with Paths that can not be parsed are left out of the Groups,
+// the rest of the File is still read
+func TestReadInvalidPath(t *testing.T) {
+	b := appendTestSegment(nil, KTocMetaData|KTocNewObjList|KTocRawData, func(s *segmentBuilder) {
+		s.u32(3)
+		s.str("/'Unquoted'/Channel")
+		s.b = append(s.b, NoRawDataValue...)
+		s.u32(0)
+		s.str(GroupPath("G"))
+		s.b = append(s.b, NoRawDataValue...)
+		s.u32(0)
+		s.str(ChannelPath("G", "C"))
+		s.index(Int32, 2)
+		s.u32(0)
+	}, func(s *segmentBuilder) {
+		s.u32(1)
+		s.u32(2)
+	})
+	// Metadata only, naming the invalid Object again
+	b = appendTestSegment(b, KTocMetaData, func(s *segmentBuilder) {
+		s.u32(1)
+		s.str("/'Unquoted'/Channel")
+		s.b = append(s.b, NoRawDataValue...)
+		s.u32(0)
+	}, nil)
+
+	f, err := NewFile(NewBytesSource(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if groups := f.Groups(); len(groups) != 1 || groups[0].Name() != "G" {
+		t.Errorf("got groups %v, want only G", groups)
+	}
+	if got, want := channelData(t, f, "G", "C"), []int32{1, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	issues := Verify(NewBytesSource(b))
+	if len(issues) != 1 {
+		t.Fatalf("got issues %v, want 1", issues)
+	}
+	var tdmsErr *Error
+	issue := issues[0]
+	if issue.Segment != 0 || issue.Offset != leadInSize || !errors.Is(issue.Err, ErrInvalidPath) ||
+		!errors.As(issue.Err, &tdmsErr) || tdmsErr.Path != "/'Unquoted'/Channel" {
+		t.Errorf("got issue %+v, want ErrInvalidPath at %d", issue, leadInSize)
+	}
+}
@@ -0,0 +1,178 @@
+package tdms
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/big"
+	"time"
+)
+
+// Decoded value of the Property
+//
+// The Go type depends on the Data Type:
+// - Int8, Int16, Int32, Int64 as int8, int16, int32, int64
+// - Uint8, Uint16, Uint32, Uint64 as uint8, uint16, uint32, uint64
+// - SGL, DBL as float32, float64
+// - EXT as *big.Float, nil for NaN
+// - Boolean as bool
+// - ComplexSGL, ComplexDBL as complex64, complex128
+// - Timestamp as LVTimestamp
+// - String as string
+// - Void as nil
+func (p Property) Value() interface{} {
+	return p.value
+}
+
+// Value of a numeric or Boolean Property as a float64
+// EXT values are rounded to the nearest float64, Booleans are 0 or 1
+func (p Property) AsFloat64() (float64, error) {
+	switch v := p.value.(type) {
+	case int8:
+		return float64(v), nil
+	case int16:
+		return float64(v), nil
+	case int32:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case uint8:
+		return float64(v), nil
+	case uint16:
+		return float64(v), nil
+	case uint32:
+		return float64(v), nil
+	case uint64:
+		return float64(v), nil
+	case float32:
+		return float64(v), nil
+	case float64:
+		return v, nil
+	case *big.Float:
+		return extFloat64(v), nil
+	case bool:
+		if v {
+			return 1, nil
+		}
+		return 0, nil
+	}
+	return 0, p.typeError("float64")
+}
+
+// Value of an integer or Boolean Property as an int64
+// Uint64 values larger than math.MaxInt64 are an error
+func (p Property) AsInt64() (int64, error) {
+	switch v := p.value.(type) {
+	case int8:
+		return int64(v), nil
+	case int16:
+		return int64(v), nil
+	case int32:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case uint8:
+		return int64(v), nil
+	case uint16:
+		return int64(v), nil
+	case uint32:
+		return int64(v), nil
+	case uint64:
+		if v > math.MaxInt64 {
+			return 0, fmt.Errorf("%w: property %s value %d overflows int64", ErrPropertyType, p.Name, v)
+		}
+		return int64(v), nil
+	case bool:
+		if v {
+			return 1, nil
+		}
+		return 0, nil
+	}
+	return 0, p.typeError("int64")
+}
+
+// Value of a Timestamp Property as an exact LVTimestamp
+func (p Property) AsTimestamp() (LVTimestamp, error) {
+	if v, ok := p.value.(LVTimestamp); ok {
+		return v, nil
+	}
+	return LVTimestamp{}, p.typeError("timestamp")
+}
+
+// Value of a Timestamp Property as a UTC time.Time
+func (p Property) AsTime() (time.Time, error) {
+	v, err := p.AsTimestamp()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return v.Time(), nil
+}
+
+// Value of a Boolean Property
+func (p Property) AsBool() (bool, error) {
+	if v, ok := p.value.(bool); ok {
+		return v, nil
+	}
+	return false, p.typeError("bool")
+}
+
+func (p Property) typeError(target string) error {
+	return fmt.Errorf("%w: property %s has type 0x%X, not %s", ErrPropertyType, p.Name, uint32(p.DataType), target)
+}
+
+// Decodes the Raw Bytes of a single fixed width value
+func decodeValue(dataType TdsDataType, b []byte, order binary.ByteOrder) interface{} {
+	switch dataType {
+	case Int8:
+		return int8(b[0])
+	case Int16:
+		return int16(order.Uint16(b))
+	case Int32:
+		return int32(order.Uint32(b))
+	case Int64:
+		return int64(order.Uint64(b))
+	case Uint8:
+		return b[0]
+	case Uint16:
+		return order.Uint16(b)
+	case Uint32:
+		return order.Uint32(b)
+	case Uint64:
+		return order.Uint64(b)
+	case SGL, SGLwUnit:
+		return math.Float32frombits(order.Uint32(b))
+	case DBL, DBLwUnit:
+		return math.Float64frombits(order.Uint64(b))
+	case EXT, EXTwUnit:
+		return decodeEXT(b, order)
+	case Boolean:
+		return b[0] != 0
+	case ComplexSGL:
+		return decodeComplexSGLArray(b, order)[0]
+	case ComplexDBL:
+		return decodeComplexDBLArray(b, order)[0]
+	case Timestamp:
+		return decodeTimestamp(b, order)
+	}
+	return nil
+}
+
+// Formats a Property value for StringValue
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float32, float64, complex64, complex128:
+		return fmt.Sprintf("%e", v)
+	case *big.Float:
+		if v == nil {
+			return "NaN"
+		}
+		return v.Text('e', 6)
+	case LVTimestamp:
+		return v.Time().String()
+	}
+	return fmt.Sprint(value)
+}
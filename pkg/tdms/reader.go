@@ -3,9 +3,10 @@ package tdms
 import (
 	"bytes"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"io"
-	"os"
+	"math/bits"
 	"strings"
 
 	log "github.com/sirupsen/logrus"
@@ -47,6 +48,8 @@ type RawDataIndex struct {
 	ArrayDimension uint32
 	NumValues      uint64
 	RawDataSize    uint64
+	// Only set for DAQmx Raw Data
+	DAQmx *DAQmxIndex
 }
 
 type TdsDataType uint64
@@ -56,6 +59,10 @@ type Property struct {
 	DataType      TdsDataType
 	ValuePosition int64
 	StringValue   string
+	// Byte Order of the Segment the Property was read from
+	ByteOrder binary.ByteOrder
+	// Decoded value, see Value
+	value interface{}
 }
 
 type Properties []Property
@@ -76,6 +83,34 @@ func (p Properties) Less(i, j int) bool {
 	return si_low < sj_low
 }
 
+// Size in Bytes of a single value of the Data Type
+// Returns 0 for Data Types that are not a fixed width
+func (t TdsDataType) Size() int {
+	switch t {
+	case Int8, Uint8, Boolean:
+		return 1
+	case Int16, Uint16:
+		return 2
+	case Int32, Uint32, SGL, SGLwUnit:
+		return 4
+	case Int64, Uint64, DBL, DBLwUnit, ComplexSGL:
+		return 8
+	case EXT, EXTwUnit, Timestamp, ComplexDBL:
+		return 16
+	}
+	return 0
+}
+
+// Whether the Object has Raw Data in the Segment
+func (o SegmentObject) HasRawData() bool {
+	return !bytes.Equal(o.RawDataIndexHeader, NoRawDataValue) && o.RawDataIndex.NumValues > 0
+}
+
+// Whether the Raw Data of the Segment is Interleaved
+func (s Segment) Interleaved() bool {
+	return (KTocInterleavedData & s.KToCMask) == KTocInterleavedData
+}
+
 // Constants
 
 const (
@@ -99,7 +134,7 @@ const (
 	Timestamp  TdsDataType = 0x44
 	ComplexSGL TdsDataType = 0x08000C
 	ComplexDBL TdsDataType = 0x10000D
-	DAQmx      TdsDataType = 0xFFFFFF
+	DAQmx      TdsDataType = 0xFFFFFFFF
 )
 
 const (
@@ -114,29 +149,19 @@ const (
 var (
 	NoRawDataValue            = []byte{255, 255, 255, 255}
 	MatchesPreviousValue      = []byte{0, 0, 0, 0}
-	DaqmxFormatChangingScaler = []byte{69, 12, 00, 00}
-	DaqmxDigitalLineScaler    = []byte{69, 13, 00, 00}
+	DaqmxFormatChangingScaler = []byte{0x69, 0x12, 00, 00}
+	DaqmxDigitalLineScaler    = []byte{0x6A, 0x12, 00, 00}
 )
 
-// Get All Segments of TDMS File
-func ReadAllSegments(file *os.File) ([]Segment, map[string]map[string]Property) {
-	// Get File Size
-	fi, err := file.Stat()
-	if err != nil {
-		log.Fatal("Could not Obtain File Stats: ", err)
-	}
-
-	_, err = file.Seek(0, 0)
-	if err != nil {
-		log.Fatal("Error return from file.Seek in readAllTDMSSegments: ", err)
-	}
-
-	// Init Variables
-	var segments []Segment
-	segmentPos := uint64(0)
-	allPrevSegObjs := make(map[string]SegmentObject)
+// Version Numbers written in the Lead In
+const (
+	Version1 uint32 = 4712
+	Version2 uint32 = 4713
+)
 
-	prevSegment := Segment{
+// The Segment before the first Segment of a File, which has no Objects
+func emptySegment() Segment {
+	return Segment{
 		0,
 		0,
 		map[string]SegmentObject{},
@@ -148,20 +173,110 @@ func ReadAllSegments(file *os.File) ([]Segment, map[string]map[string]Property)
 		0,
 		map[string]map[string]Property{},
 	}
+}
+
+// Byte Order of the Segment
+// Big Endian if kTocBigEndian is set, otherwise Little Endian
+func (l LeadInData) ByteOrder() binary.ByteOrder {
+	return byteOrder(l.ToCMask)
+}
+
+// Byte Order of the Segment
+// Big Endian if kTocBigEndian is set, otherwise Little Endian
+func (s Segment) ByteOrder() binary.ByteOrder {
+	return byteOrder(s.KToCMask)
+}
+
+func byteOrder(tocMask uint32) binary.ByteOrder {
+	if (KTocBigEndian & tocMask) == KTocBigEndian {
+		return binary.BigEndian
+	}
+	return binary.LittleEndian
+}
+
+// Get All Segments of TDMS File
+func ReadAllSegments(src Source) ([]Segment, map[string]map[string]Property, error) {
+	return readAllSegments(src, src.Size(), segmentTag)
+}
+
+// Reads the Segments of a TDMS File from its .tdms_index File
+// The Index holds every Lead In and Metadata of the File, without the
+// Raw Data, so Segment positions are those in the File of dataSize bytes
+//
+// Returns ErrStaleIndex if the Index does not cover the whole File
+func ReadIndexSegments(index Source, dataSize int64) ([]Segment, map[string]map[string]Property, error) {
+	segments, properties, err := readAllSegments(index, dataSize, indexTag)
+	if err != nil {
+		return nil, nil, err
+	}
+	// A Segment past the end of the File is clamped to it when read,
+	// so would otherwise be taken for a truncated final Segment
+	last := segments[len(segments)-1]
+	if last.Position >= uint64(dataSize) {
+		err := fmt.Errorf("%w: index has a segment at byte %d, past the end of the file at %d", ErrStaleIndex, last.Position, dataSize)
+		return nil, nil, newError("read index", int64(index.Size()), err)
+	}
+	if last.NextSegPos != uint64(dataSize) {
+		err := fmt.Errorf("%w: index ends at byte %d of %d", ErrStaleIndex, last.NextSegPos, dataSize)
+		return nil, nil, newError("read index", int64(index.Size()), err)
+	}
+	return segments, properties, nil
+}
+
+// Reads every Segment's Lead In and Metadata from src
+// For a TDMS File src is the File itself, for an Index src holds only
+// the Lead Ins and Metadata, which are followed by Raw Data in the File
+func readAllSegments(src Source, dataSize int64, tag string) ([]Segment, map[string]map[string]Property, error) {
+	// Init Variables
+	var segments []Segment
+	segmentPos := uint64(0)
+	srcPos := int64(0)
+	allPrevSegObjs := make(map[string]SegmentObject)
+	var buf []byte
+
+	prevSegment := emptySegment()
 
 	// Iterate through Segments
 	for {
-		newSegment := ReadSegment(file, int64(segmentPos), 0, prevSegment, allPrevSegObjs)
+		// Lead In and Metadata are read in one go, then parsed from memory
+		// A File cut short within the final Segment's Lead In or Metadata
+		// keeps the Segments before it
+		file, nextBuf, err := readSegmentBuffer(src, srcPos, buf)
+		if err != nil {
+			if len(segments) > 0 && errors.Is(err, ErrTruncatedSegment) && metadataCut(src, srcPos) {
+				log.Debugf("Final segment at %d is cut short: %v", segmentPos, err)
+				break
+			}
+			return segments, nil, err
+		}
+		buf = nextBuf
+
+		// Positions are always those in the TDMS File
+		file.base, file.pos, file.size = int64(segmentPos), int64(segmentPos), dataSize
+
+		newSegment, err := readSegment(file, int64(segmentPos), io.SeekStart, prevSegment, allPrevSegObjs, tag)
+		if err != nil {
+			if len(segments) > 0 && errors.Is(err, ErrTruncatedSegment) && metadataCut(src, srcPos) {
+				log.Debugf("Final segment at %d is cut short: %v", segmentPos, err)
+				break
+			}
+			return segments, nil, err
+		}
 
 		segments = append(segments, newSegment)
 		prevSegment = newSegment
 		segmentPos = newSegment.NextSegPos
+		if tag == indexTag {
+			srcPos += int64(newSegment.DataPos - newSegment.Position)
+		} else {
+			srcPos = int64(segmentPos)
+		}
 
 		for path, val := range newSegment.Objects {
 			allPrevSegObjs[path] = val
 		}
 
-		if segmentPos >= uint64(fi.Size()) {
+		if srcPos >= src.Size() {
 			break
 		}
 	}
@@ -172,20 +287,35 @@ func ReadAllSegments(file *os.File) ([]Segment, map[string]map[string]Property)
 	objProperties := make(map[string]map[string]Property)
 	for _, seg := range segments {
 		for path, propMap := range seg.PropMap {
+			// Copied so later Segments do not overwrite this Segment's PropMap
 			_, pathPresent := objProperties[path]
 			if !pathPresent {
-				objProperties[path] = propMap
-			} else {
-				for prop, propVals := range propMap {
-					objProperties[path][prop] = propVals
-				}
+				objProperties[path] = make(map[string]Property)
+			}
+			for prop, propVals := range propMap {
+				objProperties[path][prop] = propVals
 			}
 		}
 	}
 
 	log.Debugln("Finished Reading TDMS Segments")
 
-	return segments, objProperties
+	return segments, objProperties, nil
+}
+
+// Whether the Segment at pos of src is cut short by the end of src,
+// within its Lead In or Metadata
+func metadataCut(src Source, pos int64) bool {
+	size := src.Size()
+	if pos+leadInSize > size {
+		return true
+	}
+	leadIn := make([]byte, leadInSize)
+	if readAt(src, leadIn, pos, "read lead-in") != nil {
+		return false
+	}
+	order := byteOrder(binary.LittleEndian.Uint32(leadIn[4:8]))
+	return order.Uint64(leadIn[20:28]) > uint64(size-pos-leadInSize)
 }
 
 // Reads a TDMS Segment
@@ -196,59 +326,84 @@ func ReadAllSegments(file *os.File) ([]Segment, map[string]map[string]Property)
 // A segment consists of Lead In, Meta Data, and Raw Data.
 // There are exceptions to the rules
 // hence Different Groups when written after each other will be in different seg
-func ReadSegment(file *os.File, offset int64, whence int, prevSegment Segment, allPrevSegObjs map[string]SegmentObject) Segment {
+func ReadSegment(file io.ReadSeeker, offset int64, whence int, prevSegment Segment, allPrevSegObjs map[string]SegmentObject) (Segment, error) {
+	return readSegment(file, offset, whence, prevSegment, allPrevSegObjs, segmentTag)
+}
+
+// Checks the Metadata of the Segment at pos ends within the Segment,
+// unless its Segment Length was never written
+func checkMetadataLength(leadIn LeadInData, pos int64) error {
+	if leadIn.NextSegOffset != incompleteSegLength && leadIn.RawDataOffset > leadIn.NextSegOffset {
+		err := fmt.Errorf("%w: metadata ends at byte %d, after the segment ends at %d", ErrMetadataLength, leadIn.DataPos, leadIn.NextSegPos)
+		return newError("read lead-in", pos+20, err)
+	}
+	return nil
+}
+
+// Reads a Segment starting with the given tag
+// "TDSm" in a TDMS File, "TDSh" in an Index File
+func readSegment(file io.ReadSeeker, offset int64, whence int, prevSegment Segment, allPrevSegObjs map[string]SegmentObject, tag string) (Segment, error) {
 	startPos, err := file.Seek(offset, whence)
 	if err != nil {
-		log.Fatal("Error return from file.Seek in readTDMSLeadIn: ", err)
+		return Segment{}, newError("seek segment", offset, err)
 	}
 	log.Debugf("Reading TDMS Segement starting at: %d", startPos)
 
 	// Read TDMS Lead In
 	// leadIn := readTDMSLeadIn(file, offset, whence)
-	leadIn := ReadLeadIn(file, 0, 1)
+	leadIn, err := readLeadIn(file, 0, 1, tag)
+	if err != nil {
+		return Segment{}, err
+	}
+	err = checkMetadataLength(leadIn, startPos)
+	if err != nil {
+		return Segment{}, err
+	}
 
 	// Read TDMS Meta Data objMap, objOrder, propMap := ReadMetaData(file, 0, 1, leadIn, prevSegment, allPrevSegObjs)
-	objMap, objOrder, propMap := ReadMetaData(file, 0, 1, leadIn, prevSegment, allPrevSegObjs)
-	numChunks := CalculateChunks(objMap, leadIn.NextSegPos, leadIn.DataPos)
+	objMap, objOrder, propMap, err := ReadMetaData(file, 0, 1, leadIn, prevSegment, allPrevSegObjs)
+	if err != nil {
+		return Segment{}, err
+	}
+	// A Segment cut short, e.g. by a logger losing power, has a Segment
+	// Length of 0xFFFFFFFFFFFFFFFF or one past the end of the File
+	// The whole Chunks and values of the final Chunk that were written
+	// are still read
+	fileSize, err := file.Seek(0, io.SeekEnd)
+	if err != nil {
+		return Segment{}, newError("seek end", startPos, err)
+	}
+	nextSegPos := leadIn.NextSegPos
+	var numChunks, finalChunkLength uint64
+	if leadIn.NextSegOffset == incompleteSegLength || nextSegPos > uint64(fileSize) {
+		if nextSegPos > uint64(fileSize) {
+			nextSegPos = uint64(fileSize)
+		}
+		numChunks, finalChunkLength = recoverChunks(objMap, nextSegPos, leadIn.DataPos)
+		log.Debugf("Truncated Segment: %d Chunks, Final Chunk Length: %d", numChunks, finalChunkLength)
+	} else {
+		numChunks, err = CalculateChunks(objMap, nextSegPos, leadIn.DataPos)
+		if err != nil {
+			return Segment{}, err
+		}
+	}
 
 	// Object Index
 	index := prevSegment.ObjectIndex + 1
 
-	// TODO: Finish Reading Raw Data
-	// if (0b100000 & leadIn.ToCMask) == 0b100000 {
-	// Segment Contains Interleaved Data
-	// }
-
-	// Read Data Ch by Ch
-	// for key, element := range objMap {
-	// 	switch element.dataType {
-	// 	default:
-	// 		_, err := file.Seek(int64(element.rawDataSize), 1)
-	// 		if err != nil {
-	// 			log.Fatal("Error return by file.Seek in readTDMSSegment: ", err)
-	// 		}
-	// 	case DBL:
-	// 		data := DBLArrayFromTDMS(file, int64(element.numValues), 0, 1)
-	// 		dataMin, dataMax := minMaxFloat64Slice(data)
-	// 		log.Debugf("Read %s Values\n", key)
-	// 		log.Debugf("Number of Values: %d\n", len(data))
-	// 		log.Debugf("Max Value: %.6f\n", dataMax)
-	// 		log.Debugf("Min Value: %.6f\n", dataMin)
-	// 		log.Debugf("Average Value: %.6f\n", averageFloat64Slice(data))
-	// 	}
-	// }
+	// Raw Data is not read here, see ReadSegmentChannelBytes
 	return Segment{
 		uint64(startPos),
 		numChunks,
 		objMap,
 		objOrder,
 		leadIn.ToCMask,
-		leadIn.NextSegPos,
+		nextSegPos,
 		leadIn.DataPos,
-		0, //TODO: Implement
+		finalChunkLength,
 		index,
 		propMap,
-	}
+	}, nil
 }
 
 // Reads the TDMS Lead-In (28 Bytes) of a Segment
@@ -266,22 +421,21 @@ func ReadSegment(file *os.File, offset int64, whence int, prevSegment Segment, a
 // 2 = End of File
 //
 // Returns LeadInData
-func ReadLeadIn(file *os.File, offset int64, whence int) LeadInData {
-	segmentStartPos, err := file.Seek(offset, whence)
-	if err != nil {
-		log.Fatal("Error return from file.Seek in readTDMSLeadIn: ", err)
-	}
+func ReadLeadIn(file io.ReadSeeker, offset int64, whence int) (LeadInData, error) {
+	return readLeadIn(file, offset, whence, segmentTag)
+}
 
+// Reads a Lead In starting with the given tag
+func readLeadIn(file io.ReadSeeker, offset int64, whence int, tag string) (LeadInData, error) {
 	log.Debugln("READING LEAD-IN")
 
 	// Starts with a 4-byte tag that identifies a TDMS Segment ("TDSm")
-	segStartTag := make([]byte, 4)
-	_, err = io.ReadFull(file, segStartTag)
+	segStartTag, segmentStartPos, err := readBytes(file, 4, offset, whence, "read lead-in")
 	if err != nil {
-		log.Fatal("Error return from io.ReadFull in readTDMSLeadIn: ", err)
+		return LeadInData{}, err
 	}
-	if string(segStartTag) != "TDSm" {
-		log.Fatal("Segment is not a TDMS")
+	if string(segStartTag) != tag {
+		return LeadInData{}, newError("read lead-in", segmentStartPos, ErrNotTDMS)
 	}
 	log.Debugln("Valid TDMS Segment Starting at: ", segmentStartPos)
 
@@ -293,7 +447,7 @@ func ReadLeadIn(file *os.File, offset int64, whence int) LeadInData {
 	tocBitMaskBytes := make([]byte, 4)
 	_, err = io.ReadFull(file, tocBitMaskBytes)
 	if err != nil {
-		log.Fatal("Error return from io.ReadFull in readTDMSLeadIn: ", err)
+		return LeadInData{}, newError("read lead-in", segmentStartPos, err)
 	}
 	tocBitMask := binary.LittleEndian.Uint32(tocBitMaskBytes)
 	log.Debugln("ToC BitMask: ", tocBitMask)
@@ -316,10 +470,17 @@ func ReadLeadIn(file *os.File, offset int64, whence int) LeadInData {
 		log.Debugln("Segment Contains New Object List")
 	}
 
+	// Lead In Tag and ToC are always Little Endian
+	// the rest of the Segment is Big Endian if kTocBigEndian is set
+	order := byteOrder(tocBitMask)
+
 	// 4 Byte Version Number
 	// 4713 = v2.0
 	// 4712 = Older
-	versionNumber := ReadUint32(file, 0, 1)
+	versionNumber, err := ReadUint32(file, 0, 1, order)
+	if err != nil {
+		return LeadInData{}, err
+	}
 	log.Debugln("Version Number: ", versionNumber)
 
 	// 8 Bytes - Length of Remaining Segment
@@ -327,25 +488,33 @@ func ReadLeadIn(file *os.File, offset int64, whence int) LeadInData {
 	// Remaining Length = Overall Length of Segment - Length of Lead in ()
 	// If an application encounters a problem writing, all bytes will = 0xFF
 	// can only happen at EOF
-	segLength := readUint64(file, 0, 1)
+	segLength, err := readUint64(file, 0, 1, order)
+	if err != nil {
+		return LeadInData{}, err
+	}
 	log.Debugln("Segment Length: ", segLength)
 
 	// 8 Bytes - Length of Metadata in Segment
 	// Also known as raw data offset
 	// If segment contains no metadata will = 0
-	metaLength := readUint64(file, 0, 1)
+	metaLength, err := readUint64(file, 0, 1, order)
+	if err != nil {
+		return LeadInData{}, err
+	}
 	log.Debugln("Metadata Length: ", metaLength)
 
-	leadInSize := uint64(28)
-
 	nextSegPos := uint64(0)
-	if segLength == 0xFFFFFFFFFFFFFFFF {
+	if segLength == incompleteSegLength {
 		log.Debugf("Segment incomplete, attempting to Read")
-		fileStat, err := file.Stat()
+		fileSize, err := file.Seek(0, io.SeekEnd)
+		if err != nil {
+			return LeadInData{}, newError("seek end", segmentStartPos, err)
+		}
+		_, err = file.Seek(segmentStartPos+int64(leadInSize), io.SeekStart)
 		if err != nil {
-			log.Fatal("Error return by file.Stat() in readTDMSLeadIn: ", err)
+			return LeadInData{}, newError("seek metadata", segmentStartPos, err)
 		}
-		nextSegPos = uint64(fileStat.Size())
+		nextSegPos = uint64(fileSize)
 	} else {
 		nextSegPos = uint64(segmentStartPos) + segLength + leadInSize
 	}
@@ -359,7 +528,7 @@ func ReadLeadIn(file *os.File, offset int64, whence int) LeadInData {
 		metaLength,
 		nextSegPos,
 		dataPos,
-	}
+	}, nil
 }
 
 // Read the TDMS MetaData of a Segment
@@ -377,10 +546,10 @@ func ReadLeadIn(file *os.File, offset int64, whence int) LeadInData {
 // 2 = End of File
 //
 // Returns Segment Objects and Properties
-func ReadMetaData(file *os.File, offset int64, whence int, leadin LeadInData, prevSegment Segment, allPrevSegObjs map[string]SegmentObject) (map[string]SegmentObject, []string, map[string]map[string]Property) {
-	_, err := file.Seek(offset, whence)
+func ReadMetaData(file io.ReadSeeker, offset int64, whence int, leadin LeadInData, prevSegment Segment, allPrevSegObjs map[string]SegmentObject) (map[string]SegmentObject, []string, map[string]map[string]Property, error) {
+	metaDataPos, err := file.Seek(offset, whence)
 	if err != nil {
-		log.Fatal("Error return from file.Seek in readTDMSObject: ", err)
+		return nil, nil, nil, newError("seek metadata", offset, err)
 	}
 
 	// Initialize Empty Map for Objects
@@ -393,10 +562,10 @@ func ReadMetaData(file *os.File, offset int64, whence int, leadin LeadInData, pr
 	// True if no MetaData
 	if (KTocMetaData & leadin.ToCMask) != KTocMetaData {
 		log.Debugln("Reuse Previous Segment Metadata")
-		return prevSegment.Objects, prevSegment.ObjectOrder, prevSegment.PropMap
+		return prevSegment.Objects, prevSegment.ObjectOrder, prevSegment.PropMap, nil
 	}
 
-	// TODO: Big Endianness with TocMask
+	order := leadin.ByteOrder()
 
 	prevSegObjectNum := len(prevSegment.Objects)
 
@@ -404,14 +573,20 @@ func ReadMetaData(file *os.File, offset int64, whence int, leadin LeadInData, pr
 	} else {
 		// There can be a list of new objects that are appended,
 		// or previous objects that are repeated with changed properties
-		objMap = prevSegment.Objects
-		objOrder = prevSegment.ObjectOrder
+		// Copied so the previous Segment is left unchanged
+		for path, obj := range prevSegment.Objects {
+			objMap[path] = obj
+		}
+		objOrder = append(objOrder, prevSegment.ObjectOrder...)
 	}
 
 	log.Debugln("READING METADATA")
 
 	// First 4 Bytes have number of objects in metadata
-	numObjects := ReadUint32(file, 0, 1)
+	numObjects, err := ReadUint32(file, 0, 1, order)
+	if err != nil {
+		return nil, nil, nil, err
+	}
 	log.Debugln("Number of Objects: ", numObjects)
 
 	// ar objects = make([]string, numObjects)
@@ -419,19 +594,21 @@ func ReadMetaData(file *os.File, offset int64, whence int, leadin LeadInData, pr
 		log.Debugf("Reading Object %d \n", i)
 
 		// Read Object Path
-		objPath := ReadString(file, 0, 1)
+		objPath, err := ReadString(file, 0, 1, order)
+		if err != nil {
+			return nil, nil, nil, err
+		}
 		log.Debugf("Object %d Path: %s\n", i, objPath)
 
 		// Read Raw Data Index/Length of Index Information
 		// FF FF FF FF means there is no raw data
 		// 69 12 00 00 DAQmx Format Changing Scaler
-		// 69 13 00 00 DAQmx Digital Line Scaler
+		// 6A 12 00 00 DAQmx Digital Line Scaler
 		// Matches Previous Segment Same Object i.e. use previous
 		// Otherwise
-		rawDataIndexHeaderBytes := make([]byte, 4)
-		_, err := io.ReadFull(file, rawDataIndexHeaderBytes)
+		rawDataIndexHeaderBytes, _, err := readBytes(file, 4, 0, 1, "read raw data index")
 		if err != nil {
-			log.Fatal("Error return from io.ReadFull in readTDMSObject: ", err)
+			return nil, nil, nil, withPath(err, objPath)
 		}
 		log.Debugf("Object Raw Data Index: % x", rawDataIndexHeaderBytes)
 
@@ -460,9 +637,13 @@ func ReadMetaData(file *os.File, offset int64, whence int, leadin LeadInData, pr
 				}
 				// New Segment Metadata OR Updates to Existing Data
 			} else {
+				rawDataIndex, err := readSegmentRawDataIndex(file, rawDataIndexHeaderBytes, leadin)
+				if err != nil {
+					return nil, nil, nil, withPath(err, objPath)
+				}
 				objMap[objPath] = SegmentObject{
 					rawDataIndexHeaderBytes,
-					ReadRawDataIndex(file, 0, 1, rawDataIndexHeaderBytes),
+					rawDataIndex,
 				}
 			}
 		} else if val, present := allPrevSegObjs[objPath]; present {
@@ -470,19 +651,13 @@ func ReadMetaData(file *os.File, offset int64, whence int, leadin LeadInData, pr
 			// reuse previous object
 			if bytes.Equal(rawDataIndexHeaderBytes, NoRawDataValue) {
 				// Reuse Segment  But Leave Data Index Information as Set Previously
-				if bytes.Equal(val.RawDataIndexHeader, NoRawDataValue) {
-					// Previous Segment has Data
-					// Copy Previos to Current, Leaving Header
-					objMap[objPath] = SegmentObject{
-						rawDataIndexHeaderBytes,
-						val.RawDataIndex,
-					}
-					objOrder = append(objOrder, objPath)
-				} else {
-					// Copy Completely
-					objMap[objPath] = val
-					objOrder = append(objOrder, objPath)
+				// The Header says there is no Raw Data in this Segment,
+				// even if the previous Segment had some
+				objMap[objPath] = SegmentObject{
+					rawDataIndexHeaderBytes,
+					val.RawDataIndex,
 				}
+				objOrder = append(objOrder, objPath)
 				// Matches Previous
 			} else if bytes.Equal(rawDataIndexHeaderBytes, MatchesPreviousValue) {
 				if !bytes.Equal(val.RawDataIndexHeader, NoRawDataValue) {
@@ -499,9 +674,13 @@ func ReadMetaData(file *os.File, offset int64, whence int, leadin LeadInData, pr
 				}
 			} else {
 				// Changed Metadata in this Section
+				rawDataIndex, err := readSegmentRawDataIndex(file, rawDataIndexHeaderBytes, leadin)
+				if err != nil {
+					return nil, nil, nil, withPath(err, objPath)
+				}
 				objMap[objPath] = SegmentObject{
 					rawDataIndexHeaderBytes,
-					ReadRawDataIndex(file, 0, 1, rawDataIndexHeaderBytes),
+					rawDataIndex,
 				}
 				objOrder = append(objOrder, objPath)
 			}
@@ -509,11 +688,15 @@ func ReadMetaData(file *os.File, offset int64, whence int, leadin LeadInData, pr
 			log.Debugf("New Segment Object: %s\n", objPath)
 			// New Segment Object
 			if bytes.Equal(rawDataIndexHeaderBytes, MatchesPreviousValue) {
-				log.Fatalln("Raw Data Index says to reuse previous, though this object has not been seen before: ", objPath)
+				return nil, nil, nil, &Error{"read metadata", metaDataPos, objPath, ErrUnknownObject}
 			} else if !bytes.Equal(rawDataIndexHeaderBytes, NoRawDataValue) {
+				rawDataIndex, err := readSegmentRawDataIndex(file, rawDataIndexHeaderBytes, leadin)
+				if err != nil {
+					return nil, nil, nil, withPath(err, objPath)
+				}
 				objMap[objPath] = SegmentObject{
 					rawDataIndexHeaderBytes,
-					ReadRawDataIndex(file, 0, 1, rawDataIndexHeaderBytes),
+					rawDataIndex,
 				}
 				objOrder = append(objOrder, objPath)
 			} else {
@@ -524,6 +707,7 @@ func ReadMetaData(file *os.File, offset int64, whence int, leadin LeadInData, pr
 						0,
 						0,
 						0,
+						nil,
 					},
 				}
 				objOrder = append(objOrder, objPath)
@@ -531,13 +715,19 @@ func ReadMetaData(file *os.File, offset int64, whence int, leadin LeadInData, pr
 		}
 
 		// Number of Object Properties
-		numProperties := ReadUint32(file, 0, 1)
+		numProperties, err := ReadUint32(file, 0, 1, order)
+		if err != nil {
+			return nil, nil, nil, withPath(err, objPath)
+		}
 		log.Debugf("Number of Object %d Properties: %d\n", i, numProperties)
 
 		// Read Properties
 		for j := uint32(0); j < numProperties; j++ {
 			log.Debugf("Reading Object %d Property %d\n", i, j)
-			property := ReadProperty(file, 0, 1)
+			property, err := ReadProperty(file, 0, 1, order)
+			if err != nil {
+				return nil, nil, nil, withPath(err, objPath)
+			}
 			// if propMap, present := propertyMap[objPath]; present {
 			if _, present := propertyMap[objPath]; present {
 				// Property Maps Exists for Path
@@ -545,59 +735,87 @@ func ReadMetaData(file *os.File, offset int64, whence int, leadin LeadInData, pr
 			} else {
 				// Property Map Doesn't exist for Path yet
 				initMap := map[string]Property{
-					property.Name: {
-						property.Name,
-						property.DataType,
-						property.ValuePosition,
-						property.StringValue,
-					},
+					property.Name: property,
 				}
 				propertyMap[objPath] = initMap
 			}
 		}
 	}
-	return objMap, objOrder, propertyMap
+	return objMap, objOrder, propertyMap, nil
 }
 
 // Reads Raw Data Index of a Segment Object
 //
 // Returns RawDataIndex
-func ReadRawDataIndex(file *os.File, offset int64, whence int, rawDataIndexHeader []byte) RawDataIndex {
-	_, err := file.Seek(offset, whence)
+func ReadRawDataIndex(file io.ReadSeeker, offset int64, whence int, rawDataIndexHeader []byte, order binary.ByteOrder) (RawDataIndex, error) {
+	indexPos, err := file.Seek(offset, whence)
 	if err != nil {
-		log.Fatal("Error return by file.Seek in readTDMSRawDataIndex: ", err)
+		return RawDataIndex{}, newError("seek raw data index", offset, err)
 	}
 
-	indexLength := binary.LittleEndian.Uint32(rawDataIndexHeader)
+	indexLength := order.Uint32(rawDataIndexHeader)
 	log.Debugf("Object Index Length: %d\n", indexLength)
 
-	dataType := TdsDataType(ReadUint32(file, 0, 1))
+	rawDataType, err := ReadUint32(file, 0, 1, order)
+	if err != nil {
+		return RawDataIndex{}, err
+	}
+	dataType := TdsDataType(rawDataType)
 	log.Debugf("Object Data Type: %d\n", dataType)
 
 	// must equal 1 for v2.0
-	arrayDimension := ReadUint32(file, 0, 1)
+	arrayDimension, err := ReadUint32(file, 0, 1, order)
+	if err != nil {
+		return RawDataIndex{}, err
+	}
 	if arrayDimension != 1 {
-		log.Fatal("Not Valid TDMS 2.0, Data Dimension is not 1")
+		return RawDataIndex{}, newError("read raw data index", indexPos, ErrInvalidDimension)
 	}
 
-	numValues := readUint64(file, 0, 1)
+	numValues, err := readUint64(file, 0, 1, order)
+	if err != nil {
+		return RawDataIndex{}, err
+	}
 	log.Debugf("Object Number of Values: %d\n", numValues)
 
-	dataSize := 0
-	switch TdsDataType(dataType) {
-	case Int8, Uint8, Boolean:
-		dataSize = 1
-	case Int16, Uint16:
-		dataSize = 2
-	case Int32, Uint32, SGL, SGLwUnit:
-		dataSize = 4
-	case Int64, Uint64, DBL, DBLwUnit:
-		dataSize = 8
-	case Timestamp:
-		dataSize = 16
+	// DAQmx Raw Data is described by Scalers and Raw Buffer Widths
+	var daqmx *DAQmxIndex
+	if indexLength == daqmxFormatChangingScaler || indexLength == daqmxDigitalLineScaler {
+		daqmx, err = readDAQmxIndex(file, indexLength == daqmxDigitalLineScaler, order)
+		if err != nil {
+			return RawDataIndex{}, err
+		}
+	}
+
+	// Only Strings and DAQmx Raw Data are not a fixed width
+	dataSize := dataType.Size()
+	if daqmx == nil && dataSize == 0 && dataType != String && numValues > 0 {
+		err := fmt.Errorf("%w: raw data type 0x%X", ErrUnsupportedDataType, rawDataType)
+		return RawDataIndex{}, newError("read raw data index", indexPos, err)
 	}
 
-	channelRawDataSize := uint64(dataSize) * uint64(arrayDimension) * numValues
+	// A corrupt Number of Values must not wrap the size around
+	width := uint64(dataSize) * uint64(arrayDimension)
+	if daqmx != nil {
+		width = daqmx.ChunkWidth()
+	}
+	overflow, channelRawDataSize := bits.Mul64(width, numValues)
+	if overflow != 0 || (daqmx != nil && width == 0 && numValues > 0) {
+		err := fmt.Errorf("%w: %d values of %d bytes", ErrInvalidChunkSize, numValues, width)
+		return RawDataIndex{}, newError("read raw data index", indexPos, err)
+	}
+	if dataType == String && daqmx == nil {
+		// Strings are variable length, so the Total Size in Bytes follows
+		channelRawDataSize, err = readUint64(file, 0, 1, order)
+		if err != nil {
+			return RawDataIndex{}, err
+		}
+		// Each String has at least its 4 Byte offset
+		if numValues > channelRawDataSize/4 {
+			err := fmt.Errorf("%w: %d strings in %d bytes", ErrInvalidChunkSize, numValues, channelRawDataSize)
+			return RawDataIndex{}, newError("read raw data index", indexPos, err)
+		}
+	}
 	log.Debugf("Channel Raw Data Size: %d\n", channelRawDataSize)
 
 	return RawDataIndex{
@@ -605,54 +823,74 @@ func ReadRawDataIndex(file *os.File, offset int64, whence int, rawDataIndexHeade
 		arrayDimension,
 		numValues,
 		channelRawDataSize,
-	}
+		daqmx,
+	}, nil
 }
 
-// Reads a single property from a Segment Object
-func ReadProperty(file *os.File, offset int64, whence int) Property {
-	_, err := file.Seek(offset, whence)
+// Reads the Raw Data Index of an Object in the Segment of the Lead In,
+// checking its Raw Data fits in the Segment
+func readSegmentRawDataIndex(file io.ReadSeeker, rawDataIndexHeader []byte, leadin LeadInData) (RawDataIndex, error) {
+	indexPos, _ := file.Seek(0, io.SeekCurrent)
+	rawDataIndex, err := ReadRawDataIndex(file, 0, 1, rawDataIndexHeader, leadin.ByteOrder())
 	if err != nil {
-		log.Fatal("Error return from file.Seek in readTDMSObject: ", err)
+		return RawDataIndex{}, err
+	}
+	// The length of an unfinished Segment is not known
+	if leadin.NextSegOffset == incompleteSegLength || leadin.RawDataOffset > leadin.NextSegOffset {
+		return rawDataIndex, nil
+	}
+	if dataLength := leadin.NextSegOffset - leadin.RawDataOffset; rawDataIndex.RawDataSize > dataLength {
+		err := fmt.Errorf("%w: %d bytes of raw data in a segment with %d", ErrInvalidChunkSize, rawDataIndex.RawDataSize, dataLength)
+		return RawDataIndex{}, newError("read raw data index", indexPos, err)
 	}
+	return rawDataIndex, nil
+}
 
+// Reads a single property from a Segment Object
+func ReadProperty(file io.ReadSeeker, offset int64, whence int, order binary.ByteOrder) (Property, error) {
 	// Property Name
-	propertyName := ReadString(file, 0, 1)
+	propertyName, err := ReadString(file, offset, whence, order)
+	if err != nil {
+		return Property{}, err
+	}
 	// log.Debugf("Property Name: %s\n", propertyName)
 
 	// Debuged in Hex
-	propertyDataType := ReadUint32(file, 0, 1)
+	propertyDataType, err := ReadUint32(file, 0, 1, order)
+	if err != nil {
+		return Property{}, err
+	}
 	propertyTdsDataType := TdsDataType(propertyDataType)
 
 	// Position for reading later
 	valuePosition, _ := file.Seek(0, 1)
 
-	// Property Value coerced to String
-	var valueString string
-
-	// TODO
-	// Finish This
-	// Converts to Properties
-	switch propertyTdsDataType {
+	var value interface{}
+	switch {
+	case propertyTdsDataType == String:
+		value, err = ReadString(file, 0, 1, order)
+	case propertyTdsDataType == Void:
+		// Void Properties have no value
+	case propertyTdsDataType.Size() > 0:
+		var valueBytes []byte
+		valueBytes, _, err = readBytes(file, int64(propertyTdsDataType.Size()), 0, 1, "read property")
+		if err == nil {
+			value = decodeValue(propertyTdsDataType, valueBytes, order)
+		}
 	default:
-		log.Fatal("Property Data Type Unkown")
-	case String:
-		valueString = ReadString(file, 0, 1)
-	case Int32:
-		valueString = fmt.Sprintf("%d", ReadInt32(file, 0, 1))
-	case Uint32:
-		valueString = fmt.Sprintf("%d", ReadUint32(file, 0, 1))
-	case Uint64:
-		valueString = fmt.Sprintf("%d", readUint64(file, 0, 1))
-	case DBL:
-		valueString = fmt.Sprintf("%e", ReadDBL(file, 0, 1))
-	case Timestamp:
-		valueString = ReadTime(file, 0, 1).String()
+		err = fmt.Errorf("%w: property %s has type 0x%X", ErrUnsupportedDataType, propertyName, propertyDataType)
+		return Property{}, newError("read property", valuePosition, err)
+	}
+	if err != nil {
+		return Property{}, err
 	}
 
 	return Property{
 		propertyName,
 		propertyTdsDataType,
 		valuePosition,
-		valueString,
-	}
+		formatValue(value),
+		order,
+		value,
+	}, nil
 }
@@ -0,0 +1,589 @@
+package tdms
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestReadRawDataIndexUnknownType(t *testing.T) {
+	path := ChannelPath("Group", "Channel")
+	b := writeTestFile(t, []WriteObject{{path, nil, []float64{1, 2, 3}}})
+
+	// Data Type follows the Lead In, Number of Objects, Path and Index Length
+	typePos := leadInSize + 4 + 4 + len(path) + 4
+	binary.LittleEndian.PutUint32(b[typePos:], 0x77)
+
+	_, err := NewFile(NewBytesSource(b))
+	if !errors.Is(err, ErrUnsupportedDataType) {
+		t.Fatalf("got %v, want ErrUnsupportedDataType", err)
+	}
+	var tdmsErr *Error
+	if !errors.As(err, &tdmsErr) || tdmsErr.Offset != int64(typePos) || tdmsErr.Path != path {
+		t.Errorf("got %v, want offset %d and path %s", err, typePos, path)
+	}
+}
+
+// Records the largest single read from a Source
+type largestReadSource struct {
+	Source
+	largest int
+}
+
+func (s *largestReadSource) ReadAt(p []byte, off int64) (int, error) {
+	if len(p) > s.largest {
+		s.largest = len(p)
+	}
+	return s.Source.ReadAt(p, off)
+}
+
+func TestReadMetadataLengthPastSegment(t *testing.T) {
+	path := ChannelPath("Group", "Channel")
+	values := make([]float64, 10000)
+	b := writeTestFile(t, []WriteObject{{path, nil, values}}, []WriteObject{{path, nil, values}})
+
+	// Metadata Length of the first Segment runs past its end
+	binary.LittleEndian.PutUint64(b[20:28], uint64(len(b)))
+
+	src := &largestReadSource{NewSource(bytes.NewReader(b), int64(len(b))), 0}
+	_, _, err := ReadAllSegments(src)
+	if !errors.Is(err, ErrMetadataLength) {
+		t.Fatalf("got %v, want ErrMetadataLength", err)
+	}
+	// Only the first Segment is read, not the rest of the File
+	segLength := binary.LittleEndian.Uint64(b[12:20])
+	if src.largest > leadInSize+int(segLength) {
+		t.Errorf("read %d bytes at once, want at most %d", src.largest, leadInSize+segLength)
+	}
+}
+
+func TestReadStringLengthPastMetadata(t *testing.T) {
+	path := ChannelPath("Group", "Channel")
+	unit, _ := NewProperty("unit", "V")
+	b := writeTestFile(t, []WriteObject{{path, []Property{unit}, []float64{1, 2, 3}}})
+
+	// Lengths of the Object Path, and of the Property Name after the
+	// Path, Raw Data Index and Number of Properties
+	pathPos := leadInSize + 4
+	namePos := pathPos + 4 + len(path) + 20 + 4
+	for _, pos := range []int{pathPos, namePos} {
+		corrupt := append([]byte(nil), b...)
+		binary.LittleEndian.PutUint32(corrupt[pos:], 0xFFFFFFF0)
+
+		var before, after runtime.MemStats
+		runtime.ReadMemStats(&before)
+		_, err := NewFile(NewBytesSource(corrupt))
+		runtime.ReadMemStats(&after)
+		if !errors.Is(err, ErrTruncatedSegment) {
+			t.Fatalf("length at %d: got %v, want ErrTruncatedSegment", pos, err)
+		}
+		var tdmsErr *Error
+		if !errors.As(err, &tdmsErr) || tdmsErr.Offset != int64(pos) {
+			t.Errorf("length at %d: got %v, want offset %d", pos, err, pos)
+		}
+		if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 1<<20 {
+			t.Errorf("length at %d: allocated %d bytes", pos, allocated)
+		}
+
+		if issues := Verify(NewBytesSource(corrupt)); len(issues) == 0 {
+			t.Errorf("length at %d: verify found no issues", pos)
+		}
+	}
+}
+
+// A File of many small Segments, as written by a logger
+func benchmarkFile(b *testing.B) []byte {
+	group := GroupPath("Group")
+	channels := []string{ChannelPath("Group", "A"), ChannelPath("Group", "B"), ChannelPath("Group", "C")}
+	segments := make([][]WriteObject, 0, 1000)
+	for i := 0; i < 1000; i++ {
+		// A changing Property keeps Metadata in every Segment
+		count, _ := NewProperty("count", int32(i))
+		objects := []WriteObject{{group, []Property{count}, nil}}
+		for _, path := range channels {
+			objects = append(objects, WriteObject{path, nil, make([]float64, 100)})
+		}
+		segments = append(segments, objects)
+	}
+	return writeTestFile(b, segments...)
+}
+
+func BenchmarkReadAllSegments(b *testing.B) {
+	data := benchmarkFile(b)
+	sources := []struct {
+		name string
+		src  Source
+	}{
+		{"Bytes", NewBytesSource(data)},
+		{"ReaderAt", NewSource(bytes.NewReader(data), int64(len(data)))},
+	}
+	for _, s := range sources {
+		b.Run(s.name, func(b *testing.B) {
+			b.SetBytes(int64(len(data)))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_, _, err := ReadAllSegments(s.src)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// Counts the reads made from an io.ReaderAt
+type countingReaderAt struct {
+	r     io.ReaderAt
+	reads int
+}
+
+func (c *countingReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	c.reads++
+	return c.r.ReadAt(p, off)
+}
+
+// Reads every Segment with ReadSegment, seeking and reading each value of
+// the Lead In and Metadata from file in turn
+// Returns the number of Segments read
+func readSegmentsSeeking(file io.ReadSeeker, size int64) (int, error) {
+	prevSegment := emptySegment()
+	allPrevSegObjs := make(map[string]SegmentObject)
+	n := 0
+	for pos := uint64(0); pos < uint64(size); n++ {
+		segment, err := ReadSegment(file, int64(pos), io.SeekStart, prevSegment, allPrevSegObjs)
+		if err != nil {
+			return n, err
+		}
+		for path, val := range segment.Objects {
+			allPrevSegObjs[path] = val
+		}
+		prevSegment = segment
+		pos = segment.NextSegPos
+	}
+	return n, nil
+}
+
+// Compares reading Segments with one buffered read each, against seeking
+// and reading each value, from memory and from an *os.File
+// Reports the reads made from the underlying io.ReaderAt per Segment
+func BenchmarkReadSegmentsBufferedVsSeeking(b *testing.B) {
+	data := benchmarkFile(b)
+	path := filepath.Join(b.TempDir(), "benchmark.tdms")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		b.Fatal(err)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer file.Close()
+
+	size := int64(len(data))
+	segments, _, err := ReadAllSegments(NewBytesSource(data))
+	if err != nil {
+		b.Fatal(err)
+	}
+	if n, err := readSegmentsSeeking(bytes.NewReader(data), size); n != len(segments) || err != nil {
+		b.Fatalf("seeking read %d segments, want %d: %v", n, len(segments), err)
+	}
+	readers := []struct {
+		name string
+		r    io.ReaderAt
+	}{
+		{"Memory", bytes.NewReader(data)},
+		{"File", file},
+	}
+	for _, reader := range readers {
+		counter := &countingReaderAt{reader.r, 0}
+		paths := []struct {
+			name string
+			read func() error
+		}{
+			{"Buffered", func() error {
+				_, _, err := ReadAllSegments(NewSource(counter, size))
+				return err
+			}},
+			{"Seeking", func() error {
+				_, err := readSegmentsSeeking(io.NewSectionReader(counter, 0, size), size)
+				return err
+			}},
+		}
+		for _, p := range paths {
+			b.Run(reader.name+"/"+p.name, func(b *testing.B) {
+				b.SetBytes(size)
+				b.ReportAllocs()
+				counter.reads = 0
+				for i := 0; i < b.N; i++ {
+					if err := p.read(); err != nil {
+						b.Fatal(err)
+					}
+				}
+				b.ReportMetric(float64(counter.reads)/float64(b.N*len(segments)), "reads/segment")
+			})
+		}
+	}
+}
+
+func TestReadCutInFinalMetadata(t *testing.T) {
+	path := ChannelPath("Group", "Channel")
+	unit, _ := NewProperty("unit", "V")
+	b := writeTestFile(t,
+		[]WriteObject{{path, nil, []float64{1, 2, 3}}},
+		[]WriteObject{{path, []Property{unit}, []float64{4, 5}}},
+	)
+	second := int(binary.LittleEndian.Uint64(b[12:20])) + leadInSize
+	metaEnd := second + leadInSize + int(binary.LittleEndian.Uint64(b[second+20:second+28]))
+
+	// Cut within the second Segment's Lead In and Metadata
+	for _, size := range []int{second + 1, second + leadInSize - 1, second + leadInSize, metaEnd - 1} {
+		f, err := NewFile(NewBytesSource(b[:size]))
+		if err != nil {
+			t.Fatalf("cut at %d: %v", size, err)
+		}
+		group, err := f.Group("Group")
+		if err != nil {
+			t.Fatal(err)
+		}
+		channel, err := group.Channel("Channel")
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := channel.Data()
+		if err != nil || len(data.([]float64)) != 3 {
+			t.Errorf("cut at %d: got %v %v, want the first Segment's values", size, data, err)
+		}
+	}
+
+	// Nothing can be read if the first Segment is cut
+	_, err := NewFile(NewBytesSource(b[:leadInSize+2]))
+	if !errors.Is(err, ErrTruncatedSegment) {
+		t.Errorf("got %v, want ErrTruncatedSegment", err)
+	}
+}
+
+// Encodes Segments by hand, independently of the Writer
+type segmentBuilder struct {
+	b     []byte
+	order binary.ByteOrder
+}
+
+func (s *segmentBuilder) u32(v uint32) {
+	var d []byte
+	s.b, d = extend(s.b, 4)
+	s.order.PutUint32(d, v)
+}
+
+func (s *segmentBuilder) u64(v uint64) {
+	var d []byte
+	s.b, d = extend(s.b, 8)
+	s.order.PutUint64(d, v)
+}
+
+func (s *segmentBuilder) str(v string) {
+	s.u32(uint32(len(v)))
+	s.b = append(s.b, v...)
+}
+
+// Raw Data Index of numValues fixed-width values
+func (s *segmentBuilder) index(dataType TdsDataType, numValues uint64) {
+	s.u32(20)
+	s.u32(uint32(dataType))
+	s.u32(1)
+	s.u64(numValues)
+}
+
+// Appends a Segment to b, its Lead In followed by the Metadata and Raw
+// Data written by meta and data in the ToC Mask's Byte Order
+func appendTestSegment(b []byte, tocMask uint32, meta func(s *segmentBuilder), data func(s *segmentBuilder)) []byte {
+	order := byteOrder(tocMask)
+	m := &segmentBuilder{nil, order}
+	if meta != nil {
+		meta(m)
+	}
+	d := &segmentBuilder{nil, order}
+	if data != nil {
+		data(d)
+	}
+
+	// The ToC Mask is always Little Endian
+	leadIn := &segmentBuilder{[]byte(segmentTag), binary.LittleEndian}
+	leadIn.u32(tocMask)
+	leadIn.order = order
+	leadIn.u32(Version2)
+	leadIn.u64(uint64(len(m.b) + len(d.b)))
+	leadIn.u64(uint64(len(m.b)))
+	b = append(b, leadIn.b...)
+	b = append(b, m.b...)
+	return append(b, d.b...)
+}
+
+func TestReadBigEndian(t *testing.T) {
+	c, d := ChannelPath("G", "C"), ChannelPath("G", "D")
+	tocMask := KTocMetaData | KTocNewObjList | KTocRawData | KTocBigEndian
+	b := appendTestSegment(nil, tocMask, func(s *segmentBuilder) {
+		s.u32(3)
+		s.str(rootPath)
+		s.b = append(s.b, NoRawDataValue...)
+		s.u32(1)
+		s.str("title")
+		s.u32(uint32(String))
+		s.str("big")
+		s.str(c)
+		s.index(Int32, 2)
+		s.u32(1)
+		s.str("scale")
+		s.u32(uint32(DBL))
+		s.u64(math.Float64bits(2.5))
+		s.str(d)
+		s.index(DBL, 2)
+		s.u32(0)
+	}, func(s *segmentBuilder) {
+		s.u32(1)
+		s.u32(0xFFFFFFFE)
+		s.u64(math.Float64bits(0.5))
+		s.u64(math.Float64bits(-1.25))
+	})
+	// Raw Data only, reusing the Metadata
+	b = appendTestSegment(b, KTocRawData|KTocBigEndian, nil, func(s *segmentBuilder) {
+		s.u32(3)
+		s.u32(4)
+		s.u64(math.Float64bits(1e100))
+		s.u64(math.Float64bits(0))
+	})
+
+	f, err := NewFile(NewBytesSource(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, segment := range f.Segments() {
+		if segment.ByteOrder() != binary.BigEndian {
+			t.Errorf("segment at %d: got %v, want big endian", segment.Position, segment.ByteOrder())
+		}
+	}
+	if got := f.Properties()["title"].Value(); got != "big" {
+		t.Errorf("title: got %v, want big", got)
+	}
+	if got := f.Properties()["title"].ByteOrder; got != binary.BigEndian {
+		t.Errorf("title: got %v, want big endian", got)
+	}
+
+	group, err := f.Group("G")
+	if err != nil {
+		t.Fatal(err)
+	}
+	channelC, err := group.Channel("C")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := channelC.Properties()["scale"].Value(); got != 2.5 {
+		t.Errorf("scale: got %v, want 2.5", got)
+	}
+	if got, err := channelC.Data(); err != nil || !reflect.DeepEqual(got, []int32{1, -2, 3, 4}) {
+		t.Errorf("C: got %v, %v, want [1 -2 3 4]", got, err)
+	}
+	channelD, err := group.Channel("D")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := channelD.Data(); err != nil || !reflect.DeepEqual(got, []float64{0.5, -1.25, 1e100, 0}) {
+		t.Errorf("D: got %v, %v, want [0.5 -1.25 1e+100 0]", got, err)
+	}
+}
+
+func TestReadInterleaved(t *testing.T) {
+	a, b := ChannelPath("G", "A"), ChannelPath("G", "B")
+	tocMask := KTocMetaData | KTocNewObjList | KTocRawData | KTocInterleavedData
+	row := func(s *segmentBuilder, i int) {
+		s.b = append(s.b, byte(i), byte(i>>8))
+		s.u32(math.Float32bits(float32(i) + 0.5))
+	}
+	// Two Chunks of two values of each Channel
+	data := appendTestSegment(nil, tocMask, func(s *segmentBuilder) {
+		s.u32(2)
+		s.str(a)
+		s.index(Int16, 2)
+		s.u32(0)
+		s.str(b)
+		s.index(SGL, 2)
+		s.u32(0)
+	}, func(s *segmentBuilder) {
+		for i := 0; i < 4; i++ {
+			row(s, i)
+		}
+	})
+	data = appendTestSegment(data, KTocRawData|KTocInterleavedData, nil, func(s *segmentBuilder) {
+		for i := 4; i < 6; i++ {
+			row(s, i)
+		}
+	})
+
+	f, err := NewFile(NewBytesSource(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := f.Segments()[0].NumChunks; got != 2 {
+		t.Errorf("got %d chunks, want 2", got)
+	}
+	if got, want := channelData(t, f, "G", "A"), []int16{0, 1, 2, 3, 4, 5}; !reflect.DeepEqual(got, want) {
+		t.Errorf("A: got %v, want %v", got, want)
+	}
+	if got, want := channelData(t, f, "G", "B"), []float32{0.5, 1.5, 2.5, 3.5, 4.5, 5.5}; !reflect.DeepEqual(got, want) {
+		t.Errorf("B: got %v, want %v", got, want)
+	}
+}
+
+func TestReadChangingModes(t *testing.T) {
+	a, b := ChannelPath("G", "A"), ChannelPath("G", "B")
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	modes := []struct{ interleaved, bigEndian bool }{{false, false}, {true, true}, {true, false}, {false, true}, {false, true}}
+	var wantA []float64
+	var wantB []int16
+	for i, mode := range modes {
+		w.Interleaved = mode.interleaved
+		w.BigEndian = mode.bigEndian
+		valuesA := []float64{float64(i), -float64(i) / 4}
+		valuesB := []int16{int16(i), int16(-i)}
+		if err := w.WriteSegment([]WriteObject{{a, nil, valuesA}, {b, nil, valuesB}}); err != nil {
+			t.Fatal(err)
+		}
+		wantA = append(wantA, valuesA...)
+		wantB = append(wantB, valuesB...)
+	}
+
+	f := readTestFile(t, buf.Bytes())
+	for i, segment := range f.Segments() {
+		if segment.Interleaved() != modes[i].interleaved || (segment.ByteOrder() == binary.BigEndian) != modes[i].bigEndian {
+			t.Errorf("segment %d: got ToC mask %#x", i, segment.KToCMask)
+		}
+	}
+	if got := channelData(t, f, "G", "A"); !reflect.DeepEqual(got, wantA) {
+		t.Errorf("A: got %v, want %v", got, wantA)
+	}
+	if got := channelData(t, f, "G", "B"); !reflect.DeepEqual(got, wantB) {
+		t.Errorf("B: got %v, want %v", got, wantB)
+	}
+}
+
+func TestReadInterleavedString(t *testing.T) {
+	tocMask := KTocMetaData | KTocNewObjList | KTocRawData | KTocInterleavedData
+	data := appendTestSegment(nil, tocMask, func(s *segmentBuilder) {
+		s.u32(1)
+		s.str(ChannelPath("G", "S"))
+		s.u32(28)
+		s.u32(uint32(String))
+		s.u32(1)
+		s.u64(1)
+		s.u64(5)
+		s.u32(0)
+	}, func(s *segmentBuilder) {
+		s.u32(1)
+		s.b = append(s.b, 'a')
+	})
+
+	f, err := NewFile(NewBytesSource(data))
+	if err == nil {
+		group, _ := f.Group("G")
+		channel, _ := group.Channel("S")
+		_, err = channel.Data()
+	}
+	if !errors.Is(err, ErrUnsupportedDataType) {
+		t.Errorf("got %v, want ErrUnsupportedDataType", err)
+	}
+}
+
+func TestReadRawDataIndexSize(t *testing.T) {
+	a, b := ChannelPath("G", "A"), ChannelPath("G", "B")
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	w.Interleaved = true
+	if err := w.WriteSegment([]WriteObject{{a, nil, []float64{1, 2}}, {b, nil, []float32{3, 4}}}); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+
+	// Data Type of A's Raw Data Index, after its Index Length
+	typePos := leadInSize + 4 + 4 + len(a) + 4
+	tests := []struct {
+		name      string
+		numValues uint64
+	}{
+		// Wraps around to the size of a single DBL value
+		{"Overflow", 0xE000000000000001},
+		{"Past Segment", 1000},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			corrupt := append([]byte(nil), data...)
+			binary.LittleEndian.PutUint64(corrupt[typePos+8:], test.numValues)
+
+			_, err := NewFile(NewBytesSource(corrupt))
+			if !errors.Is(err, ErrInvalidChunkSize) {
+				t.Fatalf("got %v, want ErrInvalidChunkSize", err)
+			}
+			var tdmsErr *Error
+			if !errors.As(err, &tdmsErr) || tdmsErr.Offset != int64(typePos) || tdmsErr.Path != a {
+				t.Errorf("got %v, want offset %d and path %s", err, typePos, a)
+			}
+			if issues := Verify(NewBytesSource(corrupt)); len(issues) == 0 {
+				t.Error("verify found no issues")
+			}
+		})
+	}
+}
+
+func TestReadStringIndexSize(t *testing.T) {
+	path := ChannelPath("G", "S")
+	data := writeTestFile(t, []WriteObject{{path, nil, []string{"a", "b"}}})
+
+	// More Strings than their offsets fit in
+	typePos := leadInSize + 4 + 4 + len(path) + 4
+	binary.LittleEndian.PutUint64(data[typePos+8:], 1000)
+	_, err := NewFile(NewBytesSource(data))
+	if !errors.Is(err, ErrInvalidChunkSize) {
+		t.Fatalf("got %v, want ErrInvalidChunkSize", err)
+	}
+}
+
+func TestReadTruncatedUnreadableLayout(t *testing.T) {
+	tocMask := KTocMetaData | KTocNewObjList | KTocRawData | KTocInterleavedData
+	// Interleaved Channels with different numbers of values
+	data := appendTestSegment(nil, tocMask, func(s *segmentBuilder) {
+		s.u32(2)
+		s.str(ChannelPath("G", "A"))
+		s.index(Int16, 2)
+		s.u32(0)
+		s.str(ChannelPath("G", "B"))
+		s.index(Int16, 1<<40)
+		s.u32(0)
+	}, func(s *segmentBuilder) {
+		s.b = append(s.b, make([]byte, 8)...)
+	})
+	// The Segment claims the Raw Data of both, but the File ends early
+	metaLength := binary.LittleEndian.Uint64(data[20:28])
+	binary.LittleEndian.PutUint64(data[12:20], metaLength+2*2+2<<40)
+
+	f, err := NewFile(NewBytesSource(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	group, err := f.Group("G")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, channel := range group.Channels() {
+		if got := channel.NumValues(); got != 0 {
+			t.Errorf("%s: got %d values, want 0", channel.Path(), got)
+		}
+		if _, err := channel.Float64(); err == nil {
+			t.Errorf("%s: read values of an unreadable layout", channel.Path())
+		}
+	}
+}
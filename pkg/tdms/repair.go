@@ -0,0 +1,230 @@
+package tdms
+
+import (
+	"io"
+	"os"
+)
+
+// What was kept of a TDMS File by Repair
+type RepairResult struct {
+	// Number of Segments written to the repaired File
+	Segments int
+	// Indexes of the Segments left out, as their Metadata or Raw Data
+	// layout could not be read
+	Dropped []int
+	// Bytes left out from the end of the File, the values of a truncated
+	// Segment's partial final Chunk that could not be recovered, or
+	// everything after an invalid Lead In
+	TruncatedBytes uint64
+	// Bytes written to the repaired File
+	Size uint64
+	// Every problem found, see Verify
+	Issues []Issue
+}
+
+// Writes a cleaned copy of a damaged TDMS File to w
+//
+// - Segments with unreadable Metadata or Raw Data layouts are left out
+// - A truncated final Segment keeps its whole Chunks, with its Segment
+// Length set to end after them
+// - The values written of its partial final Chunk are kept in a new
+// Segment after it, with Raw Data Indexes for the number of values
+// recovered, see ReadAllSegments
+// - Anything after an invalid Lead In is left out
+// - Unknown Version Numbers are set to 4713
+//
+// Segments are copied unchanged otherwise, so a File without problems is
+// copied as is
+func Repair(src Source, w io.Writer) (RepairResult, error) {
+	var result RepairResult
+	var writeErr error
+	leadIn := make([]byte, leadInSize)
+	checkSegments(src, func(i int, check segmentCheck, err error) {
+		if writeErr != nil {
+			return
+		}
+		if err != nil {
+			result.Issues = append(result.Issues, newIssue(i, check.segment.Position, err))
+			result.TruncatedBytes += uint64(src.Size()) - check.segment.Position
+			return
+		}
+		for _, err := range check.errs {
+			result.Issues = append(result.Issues, newIssue(i, check.segment.Position, err))
+		}
+
+		segment := check.segment
+		if !check.readable {
+			result.Dropped = append(result.Dropped, i)
+			return
+		}
+
+		// Only whole Chunks are kept of a truncated Segment, the values of
+		// its partial final Chunk are written to a Segment of their own
+		end := segment.NextSegPos
+		var recovered []byte
+		if check.truncated {
+			numChunks := segment.NumChunks
+			if segment.FinalChunkLengthOverride != 0 {
+				numChunks--
+			}
+			end = segment.DataPos + numChunks*chunkSize(segment.Objects)
+			result.TruncatedBytes += segment.NextSegPos - end
+			if segment.FinalChunkLengthOverride != 0 {
+				var recoveredSize uint64
+				recovered, recoveredSize, writeErr = recoverFinalChunk(src, segment, numChunks == 0)
+				if writeErr != nil {
+					return
+				}
+				result.TruncatedBytes -= recoveredSize
+			}
+			// The recovered Segment holds the Metadata of a Segment
+			// without whole Chunks
+			if numChunks == 0 && recovered != nil {
+				writeErr = writeRecovered(w, segment, recovered, &result)
+				return
+			}
+		}
+
+		writeErr = readAt(src, leadIn, int64(segment.Position), "repair segment")
+		if writeErr != nil {
+			return
+		}
+		order := segment.ByteOrder()
+		if version := order.Uint32(leadIn[8:12]); version != Version1 && version != Version2 {
+			order.PutUint32(leadIn[8:12], Version2)
+		}
+		order.PutUint64(leadIn[12:20], end-segment.Position-leadInSize)
+		_, writeErr = w.Write(leadIn)
+		if writeErr == nil {
+			start := int64(segment.Position + leadInSize)
+			_, writeErr = io.Copy(w, io.NewSectionReader(src, start, int64(end)-start))
+		}
+		if writeErr != nil {
+			writeErr = newError("repair segment", int64(segment.Position), writeErr)
+			return
+		}
+
+		result.Segments++
+		result.Size += end - segment.Position
+		if recovered != nil {
+			writeErr = writeRecovered(w, segment, recovered, &result)
+		}
+	})
+	return result, writeErr
+}
+
+// Writes a Segment from recoverFinalChunk, adding it to the result
+func writeRecovered(w io.Writer, segment Segment, recovered []byte, result *RepairResult) error {
+	if _, err := w.Write(recovered); err != nil {
+		return newError("repair segment", int64(segment.Position), err)
+	}
+	result.Segments++
+	result.Size += uint64(len(recovered))
+	return nil
+}
+
+// Encodes a Segment holding the values written of a truncated Segment's
+// partial final Chunk, with a Raw Data Index for each Channel giving the
+// number of values recovered, see chunkValues
+// Its Properties are only written if withProperties is set
+//
+// Returns the Segment and the Bytes of Raw Data recovered, or nil if no
+// values were written or the Segment holds DAQmx Raw Data
+func recoverFinalChunk(src Source, segment Segment, withProperties bool) ([]byte, uint64, error) {
+	chunk := make([]byte, segment.FinalChunkLengthOverride)
+	chunkPos := segment.DataPos + (segment.NumChunks-1)*chunkSize(segment.Objects)
+	if err := readAt(src, chunk, int64(chunkPos), "repair segment"); err != nil {
+		return nil, 0, err
+	}
+
+	order := segment.ByteOrder()
+	meta, d := extend(nil, 4)
+	order.PutUint32(d, uint32(len(segment.ObjectOrder)))
+	var data []byte
+	for _, path := range segment.ObjectOrder {
+		obj := segment.Objects[path]
+		var properties []Property
+		if withProperties {
+			properties = sortedProperties(segment.PropMap[path])
+		}
+
+		index := NoRawDataValue
+		if obj.HasRawData() {
+			if obj.RawDataIndex.DAQmx != nil {
+				return nil, 0, nil
+			}
+			layout, err := channelLayout(segment, path)
+			if err != nil {
+				return nil, 0, err
+			}
+			n := chunkValues(segment, path, layout, segment.NumChunks-1)
+			if n > 0 {
+				// Strings are only recovered whole
+				size := n * layout.width
+				if layout.width == 0 {
+					size = layout.blockSize
+				}
+				if segment.Interleaved() {
+					var values []byte
+					data, values = extend(data, int(size))
+					layout.numValues = n
+					deinterleave(values, chunk, layout)
+				} else {
+					data = append(data, chunk[layout.offset:layout.offset+size]...)
+				}
+				index = appendRawDataIndex(nil, obj.RawDataIndex.DataType, n, size, order)
+			}
+		}
+
+		var err error
+		meta, err = appendObject(meta, path, index, properties, order)
+		if err != nil {
+			return nil, 0, newError("repair segment", int64(segment.Position), err)
+		}
+	}
+	if len(data) == 0 {
+		return nil, 0, nil
+	}
+
+	tocMask := KTocMetaData | KTocNewObjList | KTocRawData | (segment.KToCMask & KTocBigEndian)
+	b := appendLeadIn(nil, tocMask, uint64(len(meta)), uint64(len(data)))
+	b = append(b, meta...)
+	return append(b, data...), uint64(len(data)), nil
+}
+
+// Writes a repaired copy of the TDMS File name to outName, see Repair,
+// along with its .tdms_index File
+// An existing outName is only replaced once the copy is complete
+func RepairFile(name string, outName string) (RepairResult, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return RepairResult{}, err
+	}
+	defer file.Close()
+	src, err := NewFileSource(file)
+	if err != nil {
+		return RepairResult{}, err
+	}
+
+	fi, err := file.Stat()
+	if err != nil {
+		return RepairResult{}, err
+	}
+
+	var result RepairResult
+	err = writeFileAtomic(outName, fi.Mode().Perm(), func(w io.Writer) error {
+		var err error
+		result, err = Repair(src, w)
+		return err
+	})
+	if err != nil {
+		return result, err
+	}
+
+	// The Index is rewritten, as the Segments may have moved
+	if result.Segments == 0 {
+		return result, nil
+	}
+	_, err = WriteIndexFile(outName)
+	return result, err
+}
@@ -0,0 +1,105 @@
+package tdms
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"testing"
+)
+
+// Values and Properties of every Object of a File, by Path
+// Channels without values are left out of the values, Property
+// ValuePositions are cleared as Repair may move them
+func fileContents(t *testing.T, b []byte) (map[string]interface{}, map[string]map[string]Property) {
+	t.Helper()
+	f, err := NewFile(NewBytesSource(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := make(map[string]interface{})
+	properties := make(map[string]map[string]Property)
+	addProperties := func(path string, objProperties map[string]Property) {
+		properties[path] = make(map[string]Property)
+		for name, property := range objProperties {
+			property.ValuePosition = 0
+			properties[path][name] = property
+		}
+	}
+	addProperties(rootPath, f.Properties())
+	for _, group := range f.Groups() {
+		addProperties(group.Path(), group.Properties())
+		for _, channel := range group.Channels() {
+			addProperties(channel.Path(), channel.Properties())
+			if channel.NumValues() == 0 {
+				continue
+			}
+			data, err := channel.Data()
+			if err != nil {
+				t.Fatal(err)
+			}
+			values[channel.Path()] = data
+		}
+	}
+	return values, properties
+}
+
+func TestRepairTruncated(t *testing.T) {
+	demo, err := os.ReadFile("../../testFiles/demo.tdms")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, b, s := ChannelPath("G", "A"), ChannelPath("G", "B"), ChannelPath("G", "S")
+	unit, _ := NewProperty("unit", "V")
+	first := []WriteObject{{a, nil, []float64{1, 2, 3}}, {b, nil, []int16{4, 5, 6}}}
+	second := []WriteObject{{a, []Property{unit}, []float64{7, 8, 9, 10}}, {b, nil, []int16{11, 12, 13, 14}}}
+	contiguous := writeTestFileMode(t, false, false, first, second)
+	interleaved := writeTestFileMode(t, true, true, first, second)
+	strings := writeTestFileMode(t, false, false, first, []WriteObject{{a, nil, []float64{7, 8}}, {s, nil, []string{"ab", "c"}}})
+
+	tests := []struct {
+		name string
+		b    []byte
+		// Bytes cut from the end of the File
+		cut int
+		// Bytes Repair should leave out
+		truncated uint64
+	}{
+		// The partial final Chunk of demo.tdms was 50833 Bytes
+		{"Demo", demo[:100000], 0, 1},
+		{"Contiguous", contiguous, 13, 3},
+		{"Contiguous Whole Channel", contiguous, 8, 0},
+		{"Interleaved", interleaved, 9, 1},
+		// Strings are only recovered whole
+		{"Strings", strings, 1, 10},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cut := test.b[:len(test.b)-test.cut]
+			wantValues, wantProperties := fileContents(t, cut)
+
+			var buf bytes.Buffer
+			result, err := Repair(NewBytesSource(cut), &buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Size != uint64(buf.Len()) {
+				t.Errorf("size %d, wrote %d bytes", result.Size, buf.Len())
+			}
+			if result.TruncatedBytes != test.truncated {
+				t.Errorf("truncated %d bytes, want %d", result.TruncatedBytes, test.truncated)
+			}
+			if issues := Verify(NewBytesSource(buf.Bytes())); len(issues) > 0 {
+				t.Fatalf("repaired file has issues: %v", issues)
+			}
+
+			values, properties := fileContents(t, buf.Bytes())
+			if !reflect.DeepEqual(values, wantValues) {
+				t.Errorf("got values %v, want %v", values, wantValues)
+			}
+			if !reflect.DeepEqual(properties, wantProperties) {
+				t.Errorf("got properties %v, want %v", properties, wantProperties)
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package tdms
+
+import (
+	"fmt"
+	"math"
+)
+
+// Input Source of a Scale that uses the unscaled Raw Data
+const rawDataInputSource uint32 = 0xFFFFFFFF
+
+// Most Scales, or Coefficients of a Polynomial Scale, a Channel can have
+const maxScalingCount = 1024
+
+// Converts raw values to scaled values
+type Scaling interface {
+	Scale(values []float64) []float64
+}
+
+// Scaling that leaves values unchanged
+type NoScaling struct{}
+
+func (NoScaling) Scale(values []float64) []float64 { return values }
+
+// y = Slope * x + Intercept
+type LinearScaling struct {
+	Slope     float64
+	Intercept float64
+}
+
+func (l LinearScaling) Scale(values []float64) []float64 {
+	scaled := make([]float64, len(values))
+	for i, v := range values {
+		scaled[i] = l.Slope*v + l.Intercept
+	}
+	return scaled
+}
+
+// y = C[0] + C[1] * x + C[2] * x^2 ...
+type PolynomialScaling struct {
+	Coefficients []float64
+}
+
+func (p PolynomialScaling) Scale(values []float64) []float64 {
+	scaled := make([]float64, len(values))
+	for i, v := range values {
+		// Horner's Method
+		y := 0.0
+		for j := len(p.Coefficients) - 1; j >= 0; j-- {
+			y = y*v + p.Coefficients[j]
+		}
+		scaled[i] = y
+	}
+	return scaled
+}
+
+// A Scale that takes its input from another Scale, or the Raw Data
+type scaleStep struct {
+	scaling     Scaling
+	inputSource uint32
+}
+
+// Scales applied in order, ending with the last Scale
+type multiScaling struct {
+	steps []scaleStep
+}
+
+func (m multiScaling) Scale(values []float64) []float64 {
+	return m.scale(uint32(len(m.steps)-1), values)
+}
+
+func (m multiScaling) scale(index uint32, values []float64) []float64 {
+	if index == rawDataInputSource {
+		return values
+	}
+	step := m.steps[index]
+	return step.scaling.Scale(m.scale(step.inputSource, values))
+}
+
+// Creates the Scaling described by a Channel's NI_Scale Properties
+// Linear and Polynomial Scales are supported
+//
+// Returns NoScaling if there are no Scales, or the Data is already scaled
+func ScalingFromProperties(properties map[string]Property) (Scaling, error) {
+	if status, present := properties["NI_Scaling_Status"]; present && status.StringValue == "scaled" {
+		return NoScaling{}, nil
+	}
+
+	if _, present := properties["NI_Number_Of_Scales"]; !present {
+		return NoScaling{}, nil
+	}
+	numScales, err := scalingCount(properties, "NI_Number_Of_Scales")
+	if err != nil {
+		return nil, err
+	}
+	if numScales == 0 {
+		return NoScaling{}, nil
+	}
+
+	steps := make([]scaleStep, 0, numScales)
+	for i := 0; i < numScales; i++ {
+		prefix := fmt.Sprintf("NI_Scale[%d]_", i)
+
+		scaleType, present := properties[prefix+"Scale_Type"]
+		if !present {
+			return nil, fmt.Errorf("%w: %sScale_Type is missing", ErrUnsupportedScaling, prefix)
+		}
+
+		var step scaleStep
+		switch scaleType.StringValue {
+		case "Linear":
+			slope, err := scalingProperty(properties, prefix+"Linear_Slope")
+			if err != nil {
+				return nil, err
+			}
+			intercept, err := scalingProperty(properties, prefix+"Linear_Y_Intercept")
+			if err != nil {
+				return nil, err
+			}
+			step.scaling = LinearScaling{slope, intercept}
+			step.inputSource, err = inputSource(properties, prefix+"Linear_Input_Source")
+			if err != nil {
+				return nil, err
+			}
+		case "Polynomial":
+			size, err := scalingCount(properties, prefix+"Polynomial_Coefficients_Size")
+			if err != nil {
+				return nil, err
+			}
+			coefficients := make([]float64, size)
+			for j := range coefficients {
+				name := fmt.Sprintf("%sPolynomial_Coefficients[%d]", prefix, j)
+				coefficients[j], err = scalingProperty(properties, name)
+				if err != nil {
+					return nil, err
+				}
+			}
+			step.scaling = PolynomialScaling{coefficients}
+			step.inputSource, err = inputSource(properties, prefix+"Polynomial_Input_Source")
+			if err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("%w: %s scale type", ErrUnsupportedScaling, scaleType.StringValue)
+		}
+
+		// Inputs must come from an earlier Scale, so there can be no cycles
+		if step.inputSource != rawDataInputSource && step.inputSource >= uint32(i) {
+			return nil, fmt.Errorf("%w: %sInput_Source %d is not an earlier scale", ErrUnsupportedScaling, prefix, step.inputSource)
+		}
+
+		steps = append(steps, step)
+	}
+
+	return multiScaling{steps}, nil
+}
+
+// Reads a numeric NI_Scale Property
+func scalingProperty(properties map[string]Property, name string) (float64, error) {
+	prop, present := properties[name]
+	if !present {
+		return 0, fmt.Errorf("%w: %s is missing", ErrUnsupportedScaling, name)
+	}
+	return propertyFloat64(prop)
+}
+
+// Reads an NI_Scale Property counting Scales or Coefficients
+// Only whole numbers from 0 to maxScalingCount are accepted, so a corrupt
+// count can not allocate without bound
+func scalingCount(properties map[string]Property, name string) (int, error) {
+	count, err := scalingProperty(properties, name)
+	if err != nil {
+		return 0, err
+	}
+	if count != math.Trunc(count) || count < 0 || count > maxScalingCount {
+		return 0, fmt.Errorf("%w: %s is %v", ErrUnsupportedScaling, name, count)
+	}
+	return int(count), nil
+}
+
+// Reads the Input Source of a Scale, the Raw Data if not present
+func inputSource(properties map[string]Property, name string) (uint32, error) {
+	prop, present := properties[name]
+	if !present {
+		return rawDataInputSource, nil
+	}
+	source, err := propertyFloat64(prop)
+	if err != nil {
+		return 0, err
+	}
+	if source != math.Trunc(source) || source < 0 || source > math.MaxUint32 {
+		return 0, fmt.Errorf("%w: %s is %v", ErrUnsupportedScaling, name, source)
+	}
+	return uint32(source), nil
+}
+
+// Reads the value of a numeric Property as a float64
+func propertyFloat64(prop Property) (float64, error) {
+	value, err := prop.AsFloat64()
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrUnsupportedScaling, err)
+	}
+	return value, nil
+}
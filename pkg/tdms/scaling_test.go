@@ -0,0 +1,128 @@
+package tdms
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+// Properties from pairs of Names and values, see NewProperty
+func testProperties(t *testing.T, pairs ...interface{}) map[string]Property {
+	t.Helper()
+	properties := make(map[string]Property)
+	for i := 0; i < len(pairs); i += 2 {
+		name := pairs[i].(string)
+		property, err := NewProperty(name, pairs[i+1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		properties[name] = property
+	}
+	return properties
+}
+
+func TestScalingFromProperties(t *testing.T) {
+	raw := []float64{-1, 0, 2}
+	tests := []struct {
+		name       string
+		properties []interface{}
+		want       []float64
+	}{
+		{"None", nil, raw},
+		{"Already Scaled", []interface{}{
+			"NI_Scaling_Status", "scaled",
+			"NI_Number_Of_Scales", int32(1),
+		}, raw},
+		{"No Scales", []interface{}{"NI_Number_Of_Scales", int32(0)}, raw},
+		{"Linear", []interface{}{
+			"NI_Number_Of_Scales", int32(1),
+			"NI_Scale[0]_Scale_Type", "Linear",
+			"NI_Scale[0]_Linear_Slope", 2.0,
+			"NI_Scale[0]_Linear_Y_Intercept", 1.0,
+		}, []float64{-1, 1, 5}},
+		{"Polynomial", []interface{}{
+			"NI_Number_Of_Scales", uint32(1),
+			"NI_Scale[0]_Scale_Type", "Polynomial",
+			"NI_Scale[0]_Polynomial_Coefficients_Size", int32(3),
+			"NI_Scale[0]_Polynomial_Coefficients[0]", 1.0,
+			"NI_Scale[0]_Polynomial_Coefficients[1]", 2.0,
+			"NI_Scale[0]_Polynomial_Coefficients[2]", float32(3),
+		}, []float64{2, 1, 17}},
+		{"Chained", []interface{}{
+			"NI_Number_Of_Scales", int32(2),
+			"NI_Scale[0]_Scale_Type", "Linear",
+			"NI_Scale[0]_Linear_Slope", 2.0,
+			"NI_Scale[0]_Linear_Y_Intercept", 0.0,
+			"NI_Scale[1]_Scale_Type", "Polynomial",
+			"NI_Scale[1]_Polynomial_Coefficients_Size", int32(2),
+			"NI_Scale[1]_Polynomial_Coefficients[0]", 10.0,
+			"NI_Scale[1]_Polynomial_Coefficients[1]", 1.0,
+			"NI_Scale[1]_Polynomial_Input_Source", uint32(0),
+		}, []float64{8, 10, 14}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			scaling, err := ScalingFromProperties(testProperties(t, test.properties...))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := scaling.Scale(raw); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestScalingFromPropertiesInvalid(t *testing.T) {
+	linear := func(numScales interface{}) []interface{} {
+		return []interface{}{
+			"NI_Number_Of_Scales", numScales,
+			"NI_Scale[0]_Scale_Type", "Linear",
+			"NI_Scale[0]_Linear_Slope", 1.0,
+			"NI_Scale[0]_Linear_Y_Intercept", 0.0,
+		}
+	}
+	polynomial := func(size interface{}) []interface{} {
+		return []interface{}{
+			"NI_Number_Of_Scales", int32(1),
+			"NI_Scale[0]_Scale_Type", "Polynomial",
+			"NI_Scale[0]_Polynomial_Coefficients_Size", size,
+		}
+	}
+	tests := []struct {
+		name       string
+		properties []interface{}
+	}{
+		{"Negative Number of Scales", linear(int32(-1))},
+		{"Fractional Number of Scales", linear(1.5)},
+		{"NaN Number of Scales", linear(math.NaN())},
+		{"Huge Number of Scales", linear(uint32(math.MaxUint32))},
+		{"Negative Coefficients Size", polynomial(int32(-1))},
+		{"NaN Coefficients Size", polynomial(math.NaN())},
+		{"Infinite Coefficients Size", polynomial(math.Inf(1))},
+		{"Huge Coefficients Size", polynomial(int64(1) << 40)},
+		{"Missing Coefficient", polynomial(int32(1))},
+		{"Missing Scale Type", []interface{}{"NI_Number_Of_Scales", int32(1)}},
+		{"Unknown Scale Type", []interface{}{
+			"NI_Number_Of_Scales", int32(1),
+			"NI_Scale[0]_Scale_Type", "Table",
+		}},
+		{"Input Source Not Earlier", append(linear(int32(1)), "NI_Scale[0]_Linear_Input_Source", uint32(0))},
+		{"Negative Input Source", append(linear(int32(1)), "NI_Scale[0]_Linear_Input_Source", int32(-2))},
+		{"String Slope", []interface{}{
+			"NI_Number_Of_Scales", int32(1),
+			"NI_Scale[0]_Scale_Type", "Linear",
+			"NI_Scale[0]_Linear_Slope", "2",
+			"NI_Scale[0]_Linear_Y_Intercept", 0.0,
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ScalingFromProperties(testProperties(t, test.properties...))
+			if !errors.Is(err, ErrUnsupportedScaling) {
+				t.Errorf("got %v, want ErrUnsupportedScaling", err)
+			}
+		})
+	}
+}
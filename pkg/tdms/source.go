@@ -0,0 +1,98 @@
+package tdms
+
+import (
+	"errors"
+	"io"
+	"os"
+	"sync"
+)
+
+// Source is anything a TDMS File can be read from
+// The Size is required to know where the final Segment ends
+type Source interface {
+	io.ReaderAt
+	Size() int64
+}
+
+// Creates a Source from an io.ReaderAt of a known size
+// e.g. An archive entry, or a file fetched into memory
+func NewSource(r io.ReaderAt, size int64) Source {
+	return io.NewSectionReader(r, 0, size)
+}
+
+// Creates a Source from an opened TDMS File
+// The File is not closed by the Source
+func NewFileSource(file *os.File) (Source, error) {
+	fi, err := file.Stat()
+	if err != nil {
+		return nil, newError("stat file", 0, err)
+	}
+	return NewSource(file, fi.Size()), nil
+}
+
+// Creates a Source from TDMS File contents held in memory
+// Values are decoded straight from b, which must not be modified
+func NewBytesSource(b []byte) Source {
+	return bytesSource(b)
+}
+
+// A Source that holds the whole File in memory
+// Values are decoded from its Bytes without copying them
+type memorySource interface {
+	Source
+	Bytes() []byte
+}
+
+type bytesSource []byte
+
+func (b bytesSource) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, errors.New("tdms: negative offset")
+	}
+	if off >= int64(len(b)) {
+		return 0, io.EOF
+	}
+	n := copy(p, b[off:])
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+func (b bytesSource) Size() int64 { return int64(len(b)) }
+
+func (b bytesSource) Bytes() []byte { return b }
+
+// Creates a Source from an io.ReadSeeker
+// The size is found by seeking to the end, reads are serialised
+// as they share the position of the io.ReadSeeker
+func NewReadSeekerSource(rs io.ReadSeeker) (Source, error) {
+	size, err := rs.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, newError("seek end", 0, err)
+	}
+	return &readSeekerSource{rs: rs, size: size}, nil
+}
+
+type readSeekerSource struct {
+	mu   sync.Mutex
+	rs   io.ReadSeeker
+	size int64
+}
+
+func (s *readSeekerSource) ReadAt(p []byte, off int64) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := s.rs.Seek(off, io.SeekStart)
+	if err != nil {
+		return 0, err
+	}
+	n, err := io.ReadFull(s.rs, p)
+	if err == io.ErrUnexpectedEOF {
+		err = io.EOF
+	}
+	return n, err
+}
+
+func (s *readSeekerSource) Size() int64 { return s.size }
@@ -0,0 +1,44 @@
+package tdms
+
+import (
+	"math/bits"
+	"time"
+)
+
+// Seconds from the LabVIEW Epoch (1904-01-01 00:00:00 UTC)
+// to the Unix Epoch (1970-01-01 00:00:00 UTC)
+const lvEpochToUnix int64 = 2082844800
+
+// A LabVIEW Timestamp as stored in a TDMS File
+type LVTimestamp struct {
+	// Seconds since the LabVIEW Epoch, 1904-01-01 00:00:00 UTC
+	Seconds int64
+	// Positive Fractions of a Second, in units of 2^-64 seconds
+	Fractions uint64
+}
+
+// Converts a time.Time to a LVTimestamp
+// Converting back with Time returns the same nanosecond
+func LVTimestampFromTime(t time.Time) LVTimestamp {
+	// Fractions = ceil(nanoseconds * 2^64 / 1e9)
+	fractions, remainder := bits.Div64(uint64(t.Nanosecond()), 0, 1e9)
+	if remainder != 0 {
+		fractions++
+	}
+	return LVTimestamp{
+		t.Unix() + lvEpochToUnix,
+		fractions,
+	}
+}
+
+// Converts the Timestamp to a time.Time in UTC
+// Fractions smaller than a nanosecond are truncated
+func (t LVTimestamp) Time() time.Time {
+	// Nanoseconds = floor(Fractions * 1e9 / 2^64)
+	nanoseconds, _ := bits.Mul64(t.Fractions, 1e9)
+	return time.Unix(t.Seconds-lvEpochToUnix, int64(nanoseconds)).UTC()
+}
+
+func (t LVTimestamp) String() string {
+	return t.Time().Format(time.RFC3339Nano)
+}
@@ -0,0 +1,73 @@
+package tdms
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLVTimestampTime(t *testing.T) {
+	// 2^64 / 1e9 = 18446744073.7..., the Fractions of one nanosecond
+	const nanosecond = 18446744074
+
+	cases := []struct {
+		name      string
+		timestamp LVTimestamp
+		want      time.Time
+	}{
+		{"Epoch", LVTimestamp{0, 0}, time.Date(1904, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"Unix Epoch", LVTimestamp{lvEpochToUnix, 0}, time.Unix(0, 0).UTC()},
+		{"Half Second", LVTimestamp{0, 1 << 63}, time.Date(1904, 1, 1, 0, 0, 0, 5e8, time.UTC)},
+		{"Before 1904", LVTimestamp{-1, 1 << 63}, time.Date(1903, 12, 31, 23, 59, 59, 5e8, time.UTC)},
+		{"Far Before 1904", LVTimestamp{-3600 * 24 * 365, 0}, time.Date(1903, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"Below A Nanosecond", LVTimestamp{0, nanosecond - 1}, time.Date(1904, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"One Nanosecond", LVTimestamp{0, nanosecond}, time.Date(1904, 1, 1, 0, 0, 0, 1, time.UTC)},
+		{"Largest Fractions", LVTimestamp{0, 1<<64 - 1}, time.Date(1904, 1, 1, 0, 0, 0, 999999999, time.UTC)},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := c.timestamp.Time(); !got.Equal(c.want) {
+				t.Errorf("got %v, want %v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestLVTimestampFromTime(t *testing.T) {
+	cases := []struct {
+		name string
+		time time.Time
+		want LVTimestamp
+	}{
+		{"Epoch", time.Date(1904, 1, 1, 0, 0, 0, 0, time.UTC), LVTimestamp{0, 0}},
+		{"Half Second", time.Date(1904, 1, 1, 0, 0, 0, 5e8, time.UTC), LVTimestamp{0, 1 << 63}},
+		{"Before 1904", time.Date(1903, 12, 31, 23, 59, 59, 5e8, time.UTC), LVTimestamp{-1, 1 << 63}},
+		{"One Nanosecond", time.Date(1904, 1, 1, 0, 0, 0, 1, time.UTC), LVTimestamp{0, 18446744074}},
+		{"Other Zone", time.Date(1904, 1, 1, 1, 0, 0, 0, time.FixedZone("", 3600)), LVTimestamp{0, 0}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := LVTimestampFromTime(c.time); got != c.want {
+				t.Errorf("got %+v, want %+v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestLVTimestampRoundTrip(t *testing.T) {
+	seconds := []int64{-lvEpochToUnix - 1, -1, 0, 1, 1700000000}
+	nanoseconds := []int{0, 1, 2, 499999999, 500000000, 500000001, 999999998, 999999999}
+	for _, s := range seconds {
+		for _, ns := range nanoseconds {
+			want := time.Unix(s, int64(ns)).UTC()
+			if got := LVTimestampFromTime(want).Time(); !got.Equal(want) {
+				t.Errorf("%v: got %v", want, got)
+			}
+		}
+	}
+	for ns := 0; ns < 1e9; ns += 999983 {
+		want := time.Unix(-lvEpochToUnix-100, int64(ns)).UTC()
+		if got := LVTimestampFromTime(want).Time(); !got.Equal(want) {
+			t.Errorf("%v: got %v", want, got)
+		}
+	}
+}
@@ -2,16 +2,16 @@ package tdms
 
 import (
 	"encoding/binary"
+	"fmt"
 	"io"
 	"math"
-	"os"
-	"strings"
+	"math/big"
 	"time"
 
 	log "github.com/sirupsen/logrus"
 )
 
-// Reads a string from a TDMS File
+// Reads size bytes from a TDMS File
 //
 // Starts at Byte Defined by Offset
 // When is the reference point for the offset
@@ -19,29 +19,76 @@ import (
 // 1 = Current Position
 // 2 = End of File
 //
-// Returns String
-func ReadString(file *os.File, offset int64, whence int) string {
-	_, err := file.Seek(offset, whence)
+// Returns the Bytes and the Position they were read from
+func readBytes(file io.ReadSeeker, size int64, offset int64, whence int, op string) ([]byte, int64, error) {
+	pos, err := file.Seek(offset, whence)
+	if err != nil {
+		return nil, pos, newError(op, offset, err)
+	}
+
+	byteArray := make([]byte, size)
+	_, err = io.ReadFull(file, byteArray)
 	if err != nil {
-		log.Fatal("Error return from file.Seek in stringFromTDMS: ", err)
+		return nil, pos, newError(op, pos, err)
+	}
+
+	return byteArray, pos, nil
+}
+
+// Bytes left to read from a File after its current Position
+func remainingBytes(file io.ReadSeeker) (int64, error) {
+	if r, ok := file.(interface{ Len() int }); ok {
+		return int64(r.Len()), nil
 	}
+	pos, err := file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return 0, err
+	}
+	end, err := file.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, err
+	}
+	_, err = file.Seek(pos, io.SeekStart)
+	return end - pos, err
+}
+
+// Reads a string from a TDMS File
+//
+// Starts at Byte Defined by Offset
+// When is the reference point for the offset
+// 0 = Beginning of File
+// 1 = Current Position
+// 2 = End of File
+// Order is the Byte Order of the Segment
+//
+// Returns String
+func ReadString(file io.ReadSeeker, offset int64, whence int, order binary.ByteOrder) (string, error) {
 	// Get Length of String
 	// Required to be in the first 4 bytes
-	stringLengthBytes := make([]byte, 4)
-	_, err = io.ReadFull(file, stringLengthBytes)
+	stringLengthBytes, pos, err := readBytes(file, 4, offset, whence, "read string")
 	if err != nil {
-		log.Fatal("Error return from io.ReadFull in stringFromTDMS: ", err)
+		return "", err
+	}
+	stringLength := order.Uint32(stringLengthBytes)
+
+	// A corrupt length must not allocate more than is left to read
+	remaining, err := remainingBytes(file)
+	if err != nil {
+		return "", newError("read string", pos, err)
+	}
+	if int64(stringLength) > remaining {
+		err := fmt.Errorf("%w: string of %d bytes, %d bytes remain", ErrTruncatedSegment, stringLength, remaining)
+		return "", newError("read string", pos, err)
 	}
-	stringLength := binary.LittleEndian.Uint32(stringLengthBytes)
 
 	// Get String Bytes
 	stringBytes := make([]byte, stringLength)
 	_, err = io.ReadFull(file, stringBytes)
 	if err != nil {
-		log.Fatal("Error return from io.ReadFull in stringFromTDMS: ", err)
+		return "", newError("read string", pos, err)
 	}
 
-	return string(stringBytes)
+	return string(stringBytes), nil
 }
 
 // Reads an int32 from a TDMS File
@@ -51,11 +98,12 @@ func ReadString(file *os.File, offset int64, whence int) string {
 // 0 = Beginning of File
 // 1 = Current Position
 // 2 = End of File
+// Order is the Byte Order of the Segment
 //
 // Returns int32
-func ReadInt32(file *os.File, offset int64, whence int) int32 {
-	value := ReadUint32(file, offset, whence)
-	return int32(value)
+func ReadInt32(file io.ReadSeeker, offset int64, whence int, order binary.ByteOrder) (int32, error) {
+	value, err := ReadUint32(file, offset, whence, order)
+	return int32(value), err
 }
 
 // Reads a single uint32 from a TDMS File
@@ -65,132 +113,299 @@ func ReadInt32(file *os.File, offset int64, whence int) int32 {
 // 0 = Beginning of File
 // 1 = Current Position
 // 2 = End of File
+// Order is the Byte Order of the Segment
 //
 // Returns uint32
-func ReadUint32(file *os.File, offset int64, whence int) uint32 {
-	_, err := file.Seek(offset, whence)
+func ReadUint32(file io.ReadSeeker, offset int64, whence int, order binary.ByteOrder) (uint32, error) {
+	intBytes, _, err := readBytes(file, 4, offset, whence, "read uint32")
 	if err != nil {
-		log.Fatal("Error return from file.Seek in uint32FromTDMS: ", err)
+		return 0, err
 	}
+	intNumber := order.Uint32(intBytes)
+
+	return intNumber, nil
+}
 
-	intBytes := make([]byte, 4)
-	_, err = io.ReadFull(file, intBytes)
+// Reads a []uint32 from a TDMS File
+//
+// Starts at Byte Defined by Offset
+// When is the reference point for the offset
+// 0 = Beginning of File
+// 1 = Current Position
+// 2 = End of File
+// Order is the Byte Order of the Segment
+//
+// Returns []uint32
+func ReadUint32Array(file io.ReadSeeker, number int64, offset int64, whence int, order binary.ByteOrder) ([]uint32, error) {
+	intByteArray, _, err := readBytes(file, number*4, offset, whence, "read uint32 array")
 	if err != nil {
-		log.Fatal("Error return from io.ReadFull in uint32FromTDMS: ", err)
+		return nil, err
 	}
-	intNumber := binary.LittleEndian.Uint32(intBytes)
 
-	return intNumber
+	return decodeUint32Array(intByteArray, order), nil
 }
 
-// Reads a []uint32 from a TDMS File
+// Reads an int64 from a TDMS File
 //
 // Starts at Byte Defined by Offset
 // When is the reference point for the offset
 // 0 = Beginning of File
 // 1 = Current Position
 // 2 = End of File
+// Order is the Byte Order of the Segment
 //
-// Returns []uint32
-func ReadUint32Array(file *os.File, number int64, offset int64, whence int) []uint32 {
-	size := int64(4)
+// Returns int64
+func readInt64(file io.ReadSeeker, offset int64, whence int, order binary.ByteOrder) (int64, error) {
+	value, err := readUint64(file, offset, whence, order)
+	return int64(value), err
+}
 
-	_, err := file.Seek(offset, whence)
+// Reads a uint64 from a TDMS File
+//
+// Starts at Byte Defined by Offset
+// When is the reference point for the offset
+// 0 = Beginning of File
+// 1 = Current Position
+// 2 = End of File
+// Order is the Byte Order of the Segment
+//
+// Returns uint64
+func readUint64(file io.ReadSeeker, offset int64, whence int, order binary.ByteOrder) (uint64, error) {
+	intBytes, _, err := readBytes(file, 8, offset, whence, "read uint64")
 	if err != nil {
-		log.Fatal("Error return from file.Seek in uint32FromTDMS: ", err)
+		return 0, err
 	}
+	intNumber := order.Uint64(intBytes)
+
+	return intNumber, nil
+}
 
-	intByteArray := make([]byte, number*size)
-	_, err = io.ReadFull(file, intByteArray)
+// Reads a []uint64 from a TDMS File
+//
+// Starts at Byte Defined by Offset
+// When is the reference point for the offset
+// 0 = Beginning of File
+// 1 = Current Position
+// 2 = End of File
+// Order is the Byte Order of the Segment
+//
+// Returns []uint64
+func ReadUint64Array(file io.ReadSeeker, number int64, offset int64, whence int, order binary.ByteOrder) ([]uint64, error) {
+	intByteArray, _, err := readBytes(file, number*8, offset, whence, "read uint64 array")
 	if err != nil {
-		log.Fatal("Error return from io.ReadFull in uint32FromTDMS: ", err)
+		return nil, err
 	}
 
-	var vals []uint32
+	return decodeUint64Array(intByteArray, order), nil
+}
 
-	for i := int64(0); i < number; i++ {
-		startBit := i * size
-		endBit := startBit + size
-		val := binary.LittleEndian.Uint32(intByteArray[startBit:endBit])
-		vals = append(vals, val)
+// Reads a []int8 from a TDMS File
+//
+// Starts at Byte Defined by Offset
+// When is the reference point for the offset
+// 0 = Beginning of File
+// 1 = Current Position
+// 2 = End of File
+// Order is the Byte Order of the Segment
+//
+// Returns []int8
+func ReadInt8Array(file io.ReadSeeker, number int64, offset int64, whence int, order binary.ByteOrder) ([]int8, error) {
+	byteArray, _, err := readBytes(file, number*1, offset, whence, "read int8 array")
+	if err != nil {
+		return nil, err
 	}
 
-	return vals
+	return decodeInt8Array(byteArray), nil
 }
 
-// Reads an int64 from a TDMS File
+// Reads a []int16 from a TDMS File
 //
 // Starts at Byte Defined by Offset
 // When is the reference point for the offset
 // 0 = Beginning of File
 // 1 = Current Position
 // 2 = End of File
+// Order is the Byte Order of the Segment
 //
-// Returns int64
-func readInt64(file *os.File, offset int64, whence int) int64 {
-	value := readUint64(file, offset, whence)
-	return int64(value)
+// Returns []int16
+func ReadInt16Array(file io.ReadSeeker, number int64, offset int64, whence int, order binary.ByteOrder) ([]int16, error) {
+	byteArray, _, err := readBytes(file, number*2, offset, whence, "read int16 array")
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeInt16Array(byteArray, order), nil
 }
 
-// Reads a uint64 from a TDMS File
+// Reads a []int32 from a TDMS File
 //
 // Starts at Byte Defined by Offset
 // When is the reference point for the offset
 // 0 = Beginning of File
 // 1 = Current Position
 // 2 = End of File
+// Order is the Byte Order of the Segment
 //
-// Returns uint64
-func readUint64(file *os.File, offset int64, whence int) uint64 {
-	_, err := file.Seek(offset, whence)
+// Returns []int32
+func ReadInt32Array(file io.ReadSeeker, number int64, offset int64, whence int, order binary.ByteOrder) ([]int32, error) {
+	byteArray, _, err := readBytes(file, number*4, offset, whence, "read int32 array")
 	if err != nil {
-		log.Fatal("Error return from file.Seek in uint64FromTDMS: ", err)
+		return nil, err
 	}
 
-	intBytes := make([]byte, 8)
-	_, err = io.ReadFull(file, intBytes)
+	return decodeInt32Array(byteArray, order), nil
+}
+
+// Reads a []int64 from a TDMS File
+//
+// Starts at Byte Defined by Offset
+// When is the reference point for the offset
+// 0 = Beginning of File
+// 1 = Current Position
+// 2 = End of File
+// Order is the Byte Order of the Segment
+//
+// Returns []int64
+func ReadInt64Array(file io.ReadSeeker, number int64, offset int64, whence int, order binary.ByteOrder) ([]int64, error) {
+	byteArray, _, err := readBytes(file, number*8, offset, whence, "read int64 array")
 	if err != nil {
-		log.Fatal("Error return from io.ReadFull in uint64FromTDMS: ", err)
+		return nil, err
 	}
-	intNumber := binary.LittleEndian.Uint64(intBytes)
 
-	return intNumber
+	return decodeInt64Array(byteArray, order), nil
 }
 
-// Reads a []uint64 from a TDMS File
+// Reads a []uint8 from a TDMS File
 //
 // Starts at Byte Defined by Offset
 // When is the reference point for the offset
 // 0 = Beginning of File
 // 1 = Current Position
 // 2 = End of File
+// Order is the Byte Order of the Segment
 //
-// Returns []uint64
-func readUint64Array(file *os.File, number int64, offset int64, whence int) []uint64 {
-	size := int64(8)
+// Returns []uint8
+func ReadUint8Array(file io.ReadSeeker, number int64, offset int64, whence int, order binary.ByteOrder) ([]uint8, error) {
+	byteArray, _, err := readBytes(file, number*1, offset, whence, "read uint8 array")
+	if err != nil {
+		return nil, err
+	}
 
-	_, err := file.Seek(offset, whence)
+	return decodeUint8Array(byteArray), nil
+}
+
+// Reads a []uint16 from a TDMS File
+//
+// Starts at Byte Defined by Offset
+// When is the reference point for the offset
+// 0 = Beginning of File
+// 1 = Current Position
+// 2 = End of File
+// Order is the Byte Order of the Segment
+//
+// Returns []uint16
+func ReadUint16Array(file io.ReadSeeker, number int64, offset int64, whence int, order binary.ByteOrder) ([]uint16, error) {
+	byteArray, _, err := readBytes(file, number*2, offset, whence, "read uint16 array")
 	if err != nil {
-		log.Fatal("Error return from file.Seek in uint64FromTDMS: ", err)
+		return nil, err
 	}
 
-	intByteArray := make([]byte, number*size)
-	_, err = io.ReadFull(file, intByteArray)
+	return decodeUint16Array(byteArray, order), nil
+}
+
+// Reads a []Boolean from a TDMS File
+//
+// Starts at Byte Defined by Offset
+// When is the reference point for the offset
+// 0 = Beginning of File
+// 1 = Current Position
+// 2 = End of File
+// Order is the Byte Order of the Segment
+//
+// Returns []bool
+func ReadBooleanArray(file io.ReadSeeker, number int64, offset int64, whence int, order binary.ByteOrder) ([]bool, error) {
+	byteArray, _, err := readBytes(file, number*1, offset, whence, "read Boolean array")
 	if err != nil {
-		log.Fatal("Error return from io.ReadFull in uint64FromTDMS: ", err)
+		return nil, err
 	}
 
-	var vals []uint64
+	return decodeBooleanArray(byteArray), nil
+}
 
-	for i := int64(0); i < number; i++ {
-		startBit := i * size
-		endBit := startBit + size
-		val := binary.LittleEndian.Uint64(intByteArray[startBit:endBit])
-		vals = append(vals, val)
-		// log.Debugf("Value %d: %d\n", i, val)
+// Reads a []EXT from a TDMS File
+//
+// Starts at Byte Defined by Offset
+// When is the reference point for the offset
+// 0 = Beginning of File
+// 1 = Current Position
+// 2 = End of File
+// Order is the Byte Order of the Segment
+//
+// Returns []float64
+func ReadEXTArray(file io.ReadSeeker, number int64, offset int64, whence int, order binary.ByteOrder) ([]float64, error) {
+	byteArray, _, err := readBytes(file, number*16, offset, whence, "read EXT array")
+	if err != nil {
+		return nil, err
 	}
 
-	return vals
+	return decodeEXTArray(byteArray, order), nil
+}
+
+// Reads a []EXT from a TDMS File
+//
+// Starts at Byte Defined by Offset
+// When is the reference point for the offset
+// 0 = Beginning of File
+// 1 = Current Position
+// 2 = End of File
+// Order is the Byte Order of the Segment
+//
+// Returns []*big.Float
+func ReadEXTBigArray(file io.ReadSeeker, number int64, offset int64, whence int, order binary.ByteOrder) ([]*big.Float, error) {
+	byteArray, _, err := readBytes(file, number*16, offset, whence, "read EXT array")
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeEXTBigArray(byteArray, order), nil
+}
+
+// Reads a []ComplexSGL from a TDMS File
+//
+// Starts at Byte Defined by Offset
+// When is the reference point for the offset
+// 0 = Beginning of File
+// 1 = Current Position
+// 2 = End of File
+// Order is the Byte Order of the Segment
+//
+// Returns []complex64
+func ReadComplexSGLArray(file io.ReadSeeker, number int64, offset int64, whence int, order binary.ByteOrder) ([]complex64, error) {
+	byteArray, _, err := readBytes(file, number*8, offset, whence, "read ComplexSGL array")
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeComplexSGLArray(byteArray, order), nil
+}
+
+// Reads a []ComplexDBL from a TDMS File
+//
+// Starts at Byte Defined by Offset
+// When is the reference point for the offset
+// 0 = Beginning of File
+// 1 = Current Position
+// 2 = End of File
+// Order is the Byte Order of the Segment
+//
+// Returns []complex128
+func ReadComplexDBLArray(file io.ReadSeeker, number int64, offset int64, whence int, order binary.ByteOrder) ([]complex128, error) {
+	byteArray, _, err := readBytes(file, number*16, offset, whence, "read ComplexDBL array")
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeComplexDBLArray(byteArray, order), nil
 }
 
 // Reads a SGL from a TDMS File
@@ -200,11 +415,12 @@ func readUint64Array(file *os.File, number int64, offset int64, whence int) []ui
 // 0 = Beginning of File
 // 1 = Current Position
 // 2 = End of File
+// Order is the Byte Order of the Segment
 //
 // Returns Float32
-func ReadSGL(file *os.File, offset int64, whence int) float32 {
-	value := ReadUint32(file, offset, whence)
-	return math.Float32frombits(value)
+func ReadSGL(file io.ReadSeeker, offset int64, whence int, order binary.ByteOrder) (float32, error) {
+	value, err := ReadUint32(file, offset, whence, order)
+	return math.Float32frombits(value), err
 }
 
 // Reads a Slice of SGLS from a TDMS File
@@ -214,32 +430,129 @@ func ReadSGL(file *os.File, offset int64, whence int) float32 {
 // 0 = Beginning of File
 // 1 = Current Position
 // 2 = End of File
+// Order is the Byte Order of the Segment
 //
 // Returns []Float32
-func ReadSGLArray(file *os.File, number int64, offset int64, whence int) []float32 {
+func ReadSGLArray(file io.ReadSeeker, number int64, offset int64, whence int, order binary.ByteOrder) ([]float32, error) {
 	size := int64(4)
 
-	_, err := file.Seek(offset, whence)
+	intByteArray, _, err := readBytes(file, number*size, offset, whence, "read SGL array")
 	if err != nil {
-		log.Fatal("Error return from file.Seek in DBLArrayFromTDMS: ", err)
+		return nil, err
 	}
 
-	intByteArray := make([]byte, number*size)
-	_, err = io.ReadFull(file, intByteArray)
-	if err != nil {
-		log.Fatal("Error return from io.ReadFull in DBLArrayFromTDMS: ", err)
+	return decodeSGLArray(intByteArray, order), nil
+}
+
+// Decodes Raw Bytes into a []int8
+func decodeInt8Array(b []byte) []int8 {
+	vals := make([]int8, len(b))
+	for i := range vals {
+		vals[i] = int8(b[i])
+	}
+	return vals
+}
+
+// Decodes Raw Bytes into a []int16
+func decodeInt16Array(b []byte, order binary.ByteOrder) []int16 {
+	vals := make([]int16, len(b)/2)
+	for i := range vals {
+		vals[i] = int16(order.Uint16(b[i*2:]))
 	}
+	return vals
+}
 
-	var vals []float32
+// Decodes Raw Bytes into a []int32
+func decodeInt32Array(b []byte, order binary.ByteOrder) []int32 {
+	vals := make([]int32, len(b)/4)
+	for i := range vals {
+		vals[i] = int32(order.Uint32(b[i*4:]))
+	}
+	return vals
+}
+
+// Decodes Raw Bytes into a []int64
+func decodeInt64Array(b []byte, order binary.ByteOrder) []int64 {
+	vals := make([]int64, len(b)/8)
+	for i := range vals {
+		vals[i] = int64(order.Uint64(b[i*8:]))
+	}
+	return vals
+}
+
+// Decodes Raw Bytes into a []uint8
+func decodeUint8Array(b []byte) []uint8 {
+	vals := make([]uint8, len(b))
+	copy(vals, b)
+	return vals
+}
+
+// Decodes Raw Bytes into a []uint16
+func decodeUint16Array(b []byte, order binary.ByteOrder) []uint16 {
+	vals := make([]uint16, len(b)/2)
+	for i := range vals {
+		vals[i] = order.Uint16(b[i*2:])
+	}
+	return vals
+}
+
+// Decodes Raw Bytes into a []uint32
+func decodeUint32Array(b []byte, order binary.ByteOrder) []uint32 {
+	vals := make([]uint32, len(b)/4)
+	for i := range vals {
+		vals[i] = order.Uint32(b[i*4:])
+	}
+	return vals
+}
+
+// Decodes Raw Bytes into a []uint64
+func decodeUint64Array(b []byte, order binary.ByteOrder) []uint64 {
+	vals := make([]uint64, len(b)/8)
+	for i := range vals {
+		vals[i] = order.Uint64(b[i*8:])
+	}
+	return vals
+}
+
+// Decodes Raw Bytes into a []bool, any non zero byte is true
+func decodeBooleanArray(b []byte) []bool {
+	vals := make([]bool, len(b))
+	for i := range vals {
+		vals[i] = b[i] != 0
+	}
+	return vals
+}
+
+// Decodes Raw Bytes into a []complex64
+// Each value is the Real then Imaginary SGL
+func decodeComplexSGLArray(b []byte, order binary.ByteOrder) []complex64 {
+	vals := make([]complex64, len(b)/8)
+	for i := range vals {
+		re := math.Float32frombits(order.Uint32(b[i*8:]))
+		im := math.Float32frombits(order.Uint32(b[i*8+4:]))
+		vals[i] = complex(re, im)
+	}
+	return vals
+}
 
-	for i := int64(0); i < number; i++ {
-		startBit := i * size
-		endBit := startBit + size
-		val := math.Float32frombits(binary.LittleEndian.Uint32(intByteArray[startBit:endBit]))
-		vals = append(vals, val)
-		// log.Debugf("Value %d: %.2f\n", i, val)
+// Decodes Raw Bytes into a []complex128
+// Each value is the Real then Imaginary DBL
+func decodeComplexDBLArray(b []byte, order binary.ByteOrder) []complex128 {
+	vals := make([]complex128, len(b)/16)
+	for i := range vals {
+		re := math.Float64frombits(order.Uint64(b[i*16:]))
+		im := math.Float64frombits(order.Uint64(b[i*16+8:]))
+		vals[i] = complex(re, im)
 	}
+	return vals
+}
 
+// Decodes Raw Bytes into a []float32
+func decodeSGLArray(b []byte, order binary.ByteOrder) []float32 {
+	vals := make([]float32, len(b)/4)
+	for i := range vals {
+		vals[i] = math.Float32frombits(order.Uint32(b[i*4:]))
+	}
 	return vals
 }
 
@@ -250,11 +563,12 @@ func ReadSGLArray(file *os.File, number int64, offset int64, whence int) []float
 // 0 = Beginning of File
 // 1 = Current Position
 // 2 = End of File
+// Order is the Byte Order of the Segment
 //
 // Returns Float64
-func ReadDBL(file *os.File, offset int64, whence int) float64 {
-	value := readUint64(file, offset, whence)
-	return math.Float64frombits(value)
+func ReadDBL(file io.ReadSeeker, offset int64, whence int, order binary.ByteOrder) (float64, error) {
+	value, err := readUint64(file, offset, whence, order)
+	return math.Float64frombits(value), err
 }
 
 // Reads a []DBL from a TDMS File
@@ -264,33 +578,94 @@ func ReadDBL(file *os.File, offset int64, whence int) float64 {
 // 0 = Beginning of File
 // 1 = Current Position
 // 2 = End of File
+// Order is the Byte Order of the Segment
 //
 // Returns []Float64
-func ReadDBLArray(file *os.File, number int64, offset int64, whence int) []float64 {
+func ReadDBLArray(file io.ReadSeeker, number int64, offset int64, whence int, order binary.ByteOrder) ([]float64, error) {
 	size := int64(8)
 
-	_, err := file.Seek(offset, whence)
+	intByteArray, _, err := readBytes(file, number*size, offset, whence, "read DBL array")
 	if err != nil {
-		log.Fatal("Error return from file.Seek in DBLArrayFromTDMS: ", err)
+		return nil, err
 	}
 
-	intByteArray := make([]byte, number*size)
-	_, err = io.ReadFull(file, intByteArray)
+	return decodeDBLArray(intByteArray, order), nil
+}
+
+// Decodes Raw Bytes into a []float64
+func decodeDBLArray(b []byte, order binary.ByteOrder) []float64 {
+	vals := make([]float64, len(b)/8)
+	for i := range vals {
+		vals[i] = math.Float64frombits(order.Uint64(b[i*8:]))
+	}
+	return vals
+}
+
+// Decodes the Raw Data of a String Channel
+//
+// Includes:
+// - Offset Table, the uint32 end offset of each string
+// - Concatenated UTF-8 string data
+//
+// Returns []string
+func decodeStringArray(b []byte, numValues uint64, order binary.ByteOrder) ([]string, error) {
+	tableSize := numValues * 4
+	if tableSize > uint64(len(b)) {
+		return nil, fmt.Errorf("%w: string offset table is larger than the data", ErrTruncatedSegment)
+	}
+
+	stringData := b[tableSize:]
+	vals := make([]string, numValues)
+	start := uint64(0)
+	for i := range vals {
+		end := uint64(order.Uint32(b[i*4:]))
+		if end < start || end > uint64(len(stringData)) {
+			return nil, fmt.Errorf("%w: string %d ends at %d of %d bytes", ErrInvalidChunkSize, i, end, len(stringData))
+		}
+		vals[i] = string(stringData[start:end])
+		start = end
+	}
+
+	return vals, nil
+}
+
+// Reads a Timestamp from a TDMS File
+//
+// Starts at Byte Defined by Offset
+// When is the reference point for the offset
+// 0 = Beginning of File
+// 1 = Current Position
+// 2 = End of File
+// Order is the Byte Order of the Segment
+//
+// Returns LVTimestamp
+func ReadTimestamp(file io.ReadSeeker, offset int64, whence int, order binary.ByteOrder) (LVTimestamp, error) {
+	timeBytes, _, err := readBytes(file, 16, offset, whence, "read timestamp")
 	if err != nil {
-		log.Fatal("Error return from io.ReadFull in DBLArrayFromTDMS: ", err)
+		return LVTimestamp{}, err
 	}
+	return decodeTimestamp(timeBytes, order), nil
+}
 
-	var vals []float64
+// Reads a []Timestamp from a TDMS File
+//
+// Starts at Byte Defined by Offset
+// When is the reference point for the offset
+// 0 = Beginning of File
+// 1 = Current Position
+// 2 = End of File
+// Order is the Byte Order of the Segment
+//
+// Returns []LVTimestamp
+func ReadTimestampArray(file io.ReadSeeker, number int64, offset int64, whence int, order binary.ByteOrder) ([]LVTimestamp, error) {
+	size := int64(16)
 
-	for i := int64(0); i < number; i++ {
-		startBit := i * size
-		endBit := startBit + size
-		val := math.Float64frombits(binary.LittleEndian.Uint64(intByteArray[startBit:endBit]))
-		vals = append(vals, val)
-		// log.Debugf("Value %d: %.2f\n", i, val)
+	timeByteArray, _, err := readBytes(file, number*size, offset, whence, "read timestamp array")
+	if err != nil {
+		return nil, err
 	}
 
-	return vals
+	return decodeTimestampArray(timeByteArray, order), nil
 }
 
 // Reads a Timestamp from a TDMS File
@@ -300,29 +675,67 @@ func ReadDBLArray(file *os.File, number int64, offset int64, whence int) []float
 // 0 = Beginning of File
 // 1 = Current Position
 // 2 = End of File
+// Order is the Byte Order of the Segment
 //
 // Returns time.Time
-func ReadTime(file *os.File, offset int64, whence int) time.Time {
-	posFractions := readUint64(file, offset, whence)
-	LVseconds := readInt64(file, 0, 1)
-	nanoSeconds := float64(posFractions) * math.Pow(2, -64) * 1e9
-	secondsToUnix := 2.083e9
-	timeValue := time.Unix(0, 0)
-	if LVseconds != 0 && nanoSeconds != 0 {
-		timeValue = time.Unix(LVseconds-int64(secondsToUnix), int64(nanoSeconds))
+func ReadTime(file io.ReadSeeker, offset int64, whence int, order binary.ByteOrder) (time.Time, error) {
+	timestamp, err := ReadTimestamp(file, offset, whence, order)
+	if err != nil {
+		return time.Time{}, err
 	}
-	return timeValue
+	return timestamp.Time(), nil
 }
 
-// REQUIRES
-// ObjMap/Segment.objects
-// segment.nextSegPos
-// segment.dataPos
-func CalculateChunks(objects map[string]SegmentObject, nextSegPos uint64, dataPos uint64) uint64 {
+// Decodes a single 16 Byte Timestamp
+// Little Endian has the Fractions first, Big Endian the Seconds
+func decodeTimestamp(b []byte, order binary.ByteOrder) LVTimestamp {
+	if order == binary.BigEndian {
+		return LVTimestamp{
+			int64(order.Uint64(b[0:8])),
+			order.Uint64(b[8:16]),
+		}
+	}
+	return LVTimestamp{
+		int64(order.Uint64(b[8:16])),
+		order.Uint64(b[0:8]),
+	}
+}
+
+// Decodes Raw Bytes into a []LVTimestamp
+func decodeTimestampArray(b []byte, order binary.ByteOrder) []LVTimestamp {
+	vals := make([]LVTimestamp, len(b)/16)
+	for i := range vals {
+		vals[i] = decodeTimestamp(b[i*16:i*16+16], order)
+	}
+	return vals
+}
+
+// Size in Bytes of a single Chunk of Raw Data
+// DAQmx Objects share Raw Buffers, so are only counted once
+func chunkSize(objects map[string]SegmentObject) uint64 {
 	dataSize := uint64(0)
+	daqmxSize := uint64(0)
 	for _, e := range objects {
+		if !e.HasRawData() {
+			continue
+		}
+		if e.RawDataIndex.DAQmx != nil {
+			if e.RawDataIndex.RawDataSize > daqmxSize {
+				daqmxSize = e.RawDataIndex.RawDataSize
+			}
+			continue
+		}
 		dataSize += e.RawDataIndex.RawDataSize
 	}
+	return dataSize + daqmxSize
+}
+
+// REQUIRES
+// ObjMap/Segment.objects
+// segment.nextSegPos
+// segment.dataPos
+func CalculateChunks(objects map[string]SegmentObject, nextSegPos uint64, dataPos uint64) (uint64, error) {
+	dataSize := chunkSize(objects)
 	log.Debugf("Data Size: %d", dataSize)
 
 	totalDataSize := nextSegPos - dataPos
@@ -334,21 +747,40 @@ func CalculateChunks(objects map[string]SegmentObject, nextSegPos uint64, dataPo
 	if dataSize == 0 {
 		// npTDMS: sometimes kTocRawData is set, but there isn't actually any data
 		if totalDataSize != dataSize {
-			log.Fatal("Zero channel data size but data length")
+			return 0, newError("calculate chunks", int64(dataPos), ErrInvalidChunkSize)
 		}
 		numChunks := uint64(0)
-		return numChunks
+		return numChunks, nil
 	}
 
 	// Checking for Multiple
 	chunkRemainder := totalDataSize % dataSize
 	if chunkRemainder == 0 {
 		numChunks := uint64(totalDataSize / dataSize)
-		return numChunks
+		return numChunks, nil
 	} else {
-		log.Fatal("Data Size is not a multiple of Chunk Size")
-		return uint64(0)
+		return 0, newError("calculate chunks", int64(dataPos), ErrInvalidChunkSize)
+	}
+}
+
+// Counts the Chunks of a truncated Segment, whose Raw Data may end
+// part way through a Chunk
+//
+// Returns the number of Chunks, including a partial final Chunk,
+// and the Bytes written of the final Chunk, 0 if it is whole
+func recoverChunks(objects map[string]SegmentObject, nextSegPos uint64, dataPos uint64) (uint64, uint64) {
+	dataSize := chunkSize(objects)
+	if dataSize == 0 || nextSegPos <= dataPos {
+		return 0, 0
+	}
+
+	totalDataSize := nextSegPos - dataPos
+	numChunks := totalDataSize / dataSize
+	finalChunkLength := totalDataSize % dataSize
+	if finalChunkLength != 0 {
+		numChunks++
 	}
+	return numChunks, finalChunkLength
 }
 
 func ReadAllUniqueTDMSObjects(segments []Segment) []string {
@@ -372,22 +804,23 @@ func ReadAllUniqueTDMSObjects(segments []Segment) []string {
 	return pathArray
 }
 
+// Returns the Group Paths in a list of Object Paths
 func GetGroupsFromPathArray(paths []string) []string {
 	var groups []string
 	for _, path := range paths {
-		if (path != "/") && (len(strings.Split(path, "/")) == 2) {
+		if names, err := ParsePath(path); err == nil && len(names) == 1 {
 			groups = append(groups, path)
 		}
 	}
 	return groups
 }
 
+// Returns the quoted Channel names of a Group in a list of Object Paths
 func GetChannelsFromPathArray(paths []string, group string) []string {
 	var channels []string
 	for _, path := range paths {
-		splitString := strings.Split(path, "/")
-		if (path != "/") && (len(splitString) == 3) && splitString[1] == ("'"+group+"'") {
-			channels = append(channels, splitString[2])
+		if names, err := ParsePath(path); err == nil && len(names) == 2 && names[0] == group {
+			channels = append(channels, quoteName(names[1]))
 		}
 	}
 	return channels
@@ -0,0 +1,203 @@
+package tdms
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// A problem found in a TDMS File by Verify
+type Issue struct {
+	// Index of the Segment in the File
+	Segment int
+	// Byte Offset of the problem in the File
+	Offset int64
+	Err    error
+}
+
+// A Segment read by checkSegment, and the problems found in it
+type segmentCheck struct {
+	// NumChunks includes a partial final Chunk, see FinalChunkLengthOverride
+	segment Segment
+	errs    []error
+	// Whether the Metadata and Raw Data layout could be read
+	readable bool
+	// Whether the Segment ends past the end of the File, or was never finished
+	truncated bool
+}
+
+// Checks every Segment of a TDMS File, reporting problems instead of
+// stopping at the first one
+//
+// Checks:
+// - Files too short for a Lead In, including empty Files
+// - Lead In tags and Version Numbers
+// - Segments that end past the end of the File, or were never finished
+// - Metadata that does not end where the Lead In says
+// - Raw Data Indexes and Properties that can not be read
+// - Object Paths that can not be parsed, where they first appear
+// - Objects matching previous Raw Data Indexes they never had
+// - Raw Data Types, Chunk layouts and sizes
+//
+// Segments after an invalid Lead In can not be found, so are not checked
+// Returns the Issues found, in order, none if the File is valid
+func Verify(src Source) []Issue {
+	var issues []Issue
+	checkSegments(src, func(i int, check segmentCheck, err error) {
+		if err != nil {
+			issues = append(issues, newIssue(i, check.segment.Position, err))
+		}
+		for _, err := range check.errs {
+			issues = append(issues, newIssue(i, check.segment.Position, err))
+		}
+	})
+	return issues
+}
+
+// Creates an Issue at the Offset of err, or pos if it has none
+func newIssue(segment int, pos uint64, err error) Issue {
+	offset := int64(pos)
+	var tdmsErr *Error
+	if errors.As(err, &tdmsErr) {
+		offset = tdmsErr.Offset
+	}
+	return Issue{segment, offset, err}
+}
+
+// Checks each Segment of a File in turn, calling fn with the result
+// Segments with unreadable Metadata are skipped, the following Segments
+// are checked against the Objects before them
+//
+// Stops after calling fn with an error if a Lead In can not be read
+func checkSegments(src Source, fn func(i int, check segmentCheck, err error)) {
+	size := uint64(src.Size())
+	if size == 0 {
+		err := fmt.Errorf("%w: file is empty", ErrTruncatedSegment)
+		fn(0, segmentCheck{}, newError("read lead-in", 0, err))
+		return
+	}
+	pos := uint64(0)
+	prevSegment := emptySegment()
+	allPrevSegObjs := make(map[string]SegmentObject)
+	var buf []byte
+	for i := 0; pos < size; i++ {
+		check, nextBuf, err := checkSegment(src, pos, buf, prevSegment, allPrevSegObjs)
+		buf = nextBuf
+		if err != nil {
+			check.segment.Position = pos
+			fn(i, check, err)
+			return
+		}
+		fn(i, check, nil)
+
+		if check.readable {
+			prevSegment = check.segment
+			for path, val := range check.segment.Objects {
+				allPrevSegObjs[path] = val
+			}
+		}
+		pos = check.segment.NextSegPos
+	}
+}
+
+// Reads and checks the Segment at pos
+// Returns an error if the Lead In can not be read, as the Segment's
+// end, and so the next Segment, is unknown
+func checkSegment(src Source, pos uint64, buf []byte, prevSegment Segment, allPrevSegObjs map[string]SegmentObject) (segmentCheck, []byte, error) {
+	file, buf, err := readSegmentBuffer(src, int64(pos), buf)
+	if err != nil {
+		return segmentCheck{}, buf, err
+	}
+	leadIn, err := readLeadIn(file, int64(pos), io.SeekStart, segmentTag)
+	if err != nil {
+		return segmentCheck{}, buf, err
+	}
+
+	var check segmentCheck
+	if leadIn.VersionNumber != Version1 && leadIn.VersionNumber != Version2 {
+		err := fmt.Errorf("%w: %d", ErrInvalidVersion, leadIn.VersionNumber)
+		check.errs = append(check.errs, &Error{"read lead-in", int64(pos + 8), "", err})
+	}
+
+	size := uint64(src.Size())
+	nextSegPos := leadIn.NextSegPos
+	if leadIn.NextSegOffset == incompleteSegLength {
+		check.truncated = true
+		err := fmt.Errorf("%w: segment length was never written", ErrTruncatedSegment)
+		check.errs = append(check.errs, &Error{"read lead-in", int64(pos + 12), "", err})
+	} else if nextSegPos > size || nextSegPos < pos {
+		check.truncated = true
+		err := fmt.Errorf("%w: segment ends at byte %d, past the end of the file at %d", ErrTruncatedSegment, nextSegPos, size)
+		check.errs = append(check.errs, &Error{"read lead-in", int64(pos + 12), "", err})
+		nextSegPos = size
+	}
+	check.segment = Segment{Position: pos, NextSegPos: nextSegPos, DataPos: leadIn.DataPos}
+	if leadIn.DataPos > nextSegPos {
+		err := fmt.Errorf("%w: metadata ends at byte %d, after the segment ends at %d", ErrMetadataLength, leadIn.DataPos, nextSegPos)
+		check.errs = append(check.errs, &Error{"read lead-in", int64(pos + 20), "", err})
+		return check, buf, nil
+	}
+
+	objMap, objOrder, propMap, err := ReadMetaData(file, 0, 1, leadIn, prevSegment, allPrevSegObjs)
+	if err != nil {
+		check.errs = append(check.errs, err)
+		return check, buf, nil
+	}
+	if (KTocMetaData & leadIn.ToCMask) == KTocMetaData {
+		end, _ := file.Seek(0, io.SeekCurrent)
+		if uint64(end) != leadIn.DataPos {
+			err := fmt.Errorf("%w: metadata ends at byte %d, lead-in gives %d", ErrMetadataLength, end, leadIn.DataPos)
+			check.errs = append(check.errs, &Error{"read metadata", end, "", err})
+			return check, buf, nil
+		}
+	}
+
+	for _, path := range objOrder {
+		if _, present := allPrevSegObjs[path]; present {
+			continue
+		}
+		if _, err := ParsePath(path); err != nil {
+			check.errs = append(check.errs, &Error{"read metadata", int64(pos) + leadInSize, path, err})
+		}
+	}
+
+	segment := Segment{
+		pos,
+		0,
+		objMap,
+		objOrder,
+		leadIn.ToCMask,
+		nextSegPos,
+		leadIn.DataPos,
+		0,
+		prevSegment.ObjectIndex + 1,
+		propMap,
+	}
+
+	readable := true
+	for _, path := range objOrder {
+		obj := objMap[path]
+		if !obj.HasRawData() {
+			continue
+		}
+		if _, err := channelLayout(segment, path); err != nil {
+			check.errs = append(check.errs, err)
+			readable = false
+		}
+	}
+
+	// The whole Chunks of a truncated Segment can still be read
+	if check.truncated {
+		segment.NumChunks, segment.FinalChunkLengthOverride = recoverChunks(objMap, nextSegPos, leadIn.DataPos)
+	} else {
+		segment.NumChunks, err = CalculateChunks(objMap, nextSegPos, leadIn.DataPos)
+		if err != nil {
+			check.errs = append(check.errs, err)
+			readable = false
+		}
+	}
+
+	check.segment = segment
+	check.readable = readable
+	return check, buf, nil
+}
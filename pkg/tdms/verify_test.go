@@ -0,0 +1,35 @@
+package tdms
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestVerifyShortFiles(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{"Empty", nil, ErrTruncatedSegment},
+		{"Tag Only", []byte("TD"), ErrTruncatedSegment},
+		{"Partial Lead In", []byte("TDSm\x0e\x00\x00\x00"), ErrTruncatedSegment},
+		{"Not TDMS", []byte("hello world"), ErrNotTDMS},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Read through an io.ReaderAt, as well as from memory
+			sources := []Source{
+				NewBytesSource(test.data),
+				NewSource(bytes.NewReader(test.data), int64(len(test.data))),
+			}
+			for _, src := range sources {
+				issues := Verify(src)
+				if len(issues) != 1 || !errors.Is(issues[0].Err, test.want) {
+					t.Errorf("%T: got %v, want a single %v", src, issues, test.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,290 @@
+package tdms
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Chunks appended to a Segment since the last Poll of a Watcher
+// Chunks FirstChunk to FirstChunk+NumChunks of the Segment are new
+type Update struct {
+	Segment    int
+	FirstChunk uint64
+	NumChunks  uint64
+}
+
+// Follows a TDMS File while it is being written, e.g. by LabVIEW
+//
+// Each Poll only reads what was written since the last Poll
+// A Segment is read once its Metadata is written, then its Chunks are
+// reported as they are written. The last Segment keeps growing while its
+// Segment Length is 0xFFFFFFFFFFFFFFFF or runs past the end of the File
+type Watcher struct {
+	file           *os.File
+	src            Source
+	segments       []Segment
+	properties     map[string]map[string]Property
+	prevSegment    Segment
+	allPrevSegObjs map[string]SegmentObject
+	// Position of the next Segment to read
+	nextPos uint64
+	// Whether every Chunk of the last Segment has been read
+	complete bool
+	buf      []byte
+}
+
+// Opens the TDMS File at the given path to be followed
+// Nothing is read until the first Poll, which reads the whole File
+//
+// The Watcher must be Closed once finished with
+func Watch(name string) (*Watcher, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &Watcher{
+		file:           file,
+		properties:     make(map[string]map[string]Property),
+		prevSegment:    emptySegment(),
+		allPrevSegObjs: make(map[string]SegmentObject),
+	}, nil
+}
+
+// Closes the followed File
+func (w *Watcher) Close() error { return w.file.Close() }
+
+// Segments read so far
+// The NumChunks of the last Segment is the Chunks written so far
+func (w *Watcher) Segments() []Segment { return w.segments }
+
+// Latest Properties of an Object, an empty map if it has none
+func (w *Watcher) Properties(path string) map[string]Property {
+	if properties, present := w.properties[path]; present {
+		return properties
+	}
+	return map[string]Property{}
+}
+
+// Reads the Segments and Chunks written since the last Poll
+// Incomplete Metadata or Chunks are left for a later Poll
+//
+// Returns an Update for every Segment with new Chunks, in order
+func (w *Watcher) Poll() ([]Update, error) {
+	src, err := NewFileSource(w.file)
+	if err != nil {
+		return nil, err
+	}
+	w.src = src
+	size := uint64(src.Size())
+
+	var updates []Update
+	addUpdate := func(i int) error {
+		update, err := w.growSegment(i, size)
+		if err != nil {
+			return err
+		}
+		if update.NumChunks > 0 {
+			updates = append(updates, update)
+		}
+		return nil
+	}
+
+	// The last Segment may have grown since the last Poll
+	if n := len(w.segments); n > 0 && !w.complete {
+		err := addUpdate(n - 1)
+		if err != nil || !w.complete {
+			return updates, err
+		}
+	}
+
+	for w.nextPos+leadInSize <= size {
+		segment, written, err := w.readSegment(size)
+		if err != nil {
+			return updates, err
+		}
+		if !written {
+			log.Debugf("Metadata of segment at %d not yet written", w.nextPos)
+			break
+		}
+
+		w.segments = append(w.segments, segment)
+		w.prevSegment = segment
+		w.complete = false
+		for path, val := range segment.Objects {
+			w.allPrevSegObjs[path] = val
+		}
+		for path, propMap := range segment.PropMap {
+			if _, present := w.properties[path]; !present {
+				w.properties[path] = make(map[string]Property)
+			}
+			for prop, propVals := range propMap {
+				w.properties[path][prop] = propVals
+			}
+		}
+
+		err = addUpdate(len(w.segments) - 1)
+		if err != nil || !w.complete {
+			return updates, err
+		}
+	}
+	return updates, nil
+}
+
+// Reads the Lead In and Metadata of the Segment at nextPos
+// Its NumChunks is left at 0, see growSegment
+//
+// Returns false if the Metadata is not yet completely written
+func (w *Watcher) readSegment(size uint64) (Segment, bool, error) {
+	file, buf, err := readSegmentBuffer(w.src, int64(w.nextPos), w.buf)
+	w.buf = buf
+	if err != nil {
+		return Segment{}, false, err
+	}
+
+	leadIn, err := readLeadIn(file, int64(w.nextPos), io.SeekStart, segmentTag)
+	if err != nil {
+		return Segment{}, false, err
+	}
+	err = checkMetadataLength(leadIn, int64(w.nextPos))
+	if err != nil {
+		return Segment{}, false, err
+	}
+	if leadIn.DataPos > size {
+		return Segment{}, false, nil
+	}
+
+	objMap, objOrder, propMap, err := ReadMetaData(file, 0, 1, leadIn, w.prevSegment, w.allPrevSegObjs)
+	if err != nil {
+		return Segment{}, false, err
+	}
+
+	return Segment{
+		w.nextPos,
+		0,
+		objMap,
+		objOrder,
+		leadIn.ToCMask,
+		leadIn.NextSegPos,
+		leadIn.DataPos,
+		0,
+		w.prevSegment.ObjectIndex + 1,
+		propMap,
+	}, true, nil
+}
+
+// Counts the Chunks written of the i'th Segment, updating its NumChunks
+// The Segment Length is read again, as it is only set once the
+// Segment is finished with
+//
+// Returns the Chunks written since the last count
+func (w *Watcher) growSegment(i int, size uint64) (Update, error) {
+	segment := &w.segments[i]
+
+	leadIn := make([]byte, leadInSize)
+	err := readAt(w.src, leadIn, int64(segment.Position), "read lead-in")
+	if err != nil {
+		return Update{}, err
+	}
+	segLength := segment.ByteOrder().Uint64(leadIn[12:20])
+
+	// Only whole Chunks are read while the Segment is being written
+	var numChunks uint64
+	end := segment.Position + leadInSize + segLength
+	if segLength != incompleteSegLength && end <= size {
+		numChunks, err = CalculateChunks(segment.Objects, end, segment.DataPos)
+		if err != nil {
+			return Update{}, err
+		}
+		segment.NextSegPos = end
+		w.nextPos = end
+		w.complete = true
+	} else if dataSize := chunkSize(segment.Objects); dataSize > 0 && size > segment.DataPos {
+		numChunks = (size - segment.DataPos) / dataSize
+		segment.NextSegPos = segment.DataPos + numChunks*dataSize
+	}
+
+	if numChunks < segment.NumChunks {
+		err := fmt.Errorf("%w: %d chunks, previously %d", ErrTruncatedSegment, numChunks, segment.NumChunks)
+		return Update{}, newError("poll segment", int64(segment.Position), err)
+	}
+	update := Update{i, segment.NumChunks, numChunks - segment.NumChunks}
+	segment.NumChunks = numChunks
+	return update, nil
+}
+
+// Reads the values of a Channel in an Update
+// DAQmx Channels are scaled by their NI_Scale Properties
+//
+// Returns a slice of the Channel's Data Type, or nil if the Channel has
+// no values in the Update
+func (w *Watcher) ReadUpdate(channelPath string, update Update) (interface{}, error) {
+	segment := w.segments[update.Segment]
+	obj, present := segment.Objects[channelPath]
+	if !present || !obj.HasRawData() || update.NumChunks == 0 {
+		return nil, nil
+	}
+
+	layout, err := channelLayout(segment, channelPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var data reflect.Value
+	total := layout.numValues * update.NumChunks
+	for chunk := update.FirstChunk; chunk < update.FirstChunk+update.NumChunks; chunk++ {
+		values, err := readChunkValues(w.src, segment, channelPath, layout, chunk, 0, layout.numValues)
+		if err != nil {
+			return nil, err
+		}
+		data, err = appendValues(data, values, total)
+		if err != nil {
+			return nil, &Error{"read update", int64(segment.DataPos), channelPath, err}
+		}
+	}
+
+	raw, ok := data.Interface().([]float64)
+	if ok && obj.RawDataIndex.DAQmx != nil {
+		scaling, err := ScalingFromProperties(w.Properties(channelPath))
+		if err != nil {
+			return nil, withPath(err, channelPath)
+		}
+		return scaling.Scale(raw), nil
+	}
+	return data.Interface(), nil
+}
+
+// Number of values of a Channel in an Update, without reading them
+// Returns 0 if the Channel has no values in the Update
+func (w *Watcher) UpdateNumValues(channelPath string, update Update) (uint64, error) {
+	segment := w.segments[update.Segment]
+	obj, present := segment.Objects[channelPath]
+	if !present || !obj.HasRawData() {
+		return 0, nil
+	}
+
+	layout, err := channelLayout(segment, channelPath)
+	if err != nil {
+		return 0, err
+	}
+	return layout.numValues * update.NumChunks, nil
+}
+
+// Reads the values of a numeric Channel in an Update as float64
+// Booleans are converted to 0 or 1, Complex values to their magnitude
+func (w *Watcher) ReadUpdateFloat64(channelPath string, update Update) ([]float64, error) {
+	values, err := w.ReadUpdate(channelPath, update)
+	if err != nil || values == nil {
+		return nil, err
+	}
+
+	converted, ok := toFloat64Slice(values)
+	if !ok {
+		err := fmt.Errorf("%w: %T is not numeric", ErrUnsupportedDataType, values)
+		return nil, &Error{"read update", int64(w.segments[update.Segment].DataPos), channelPath, err}
+	}
+	return converted, nil
+}
@@ -0,0 +1,171 @@
+package tdms
+
+import (
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// A File being written, and a Watcher following it
+type watchTest struct {
+	t       *testing.T
+	file    *os.File
+	watcher *Watcher
+}
+
+func newWatchTest(t *testing.T) *watchTest {
+	name := filepath.Join(t.TempDir(), "watched.tdms")
+	file, err := os.Create(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { file.Close() })
+	watcher, err := Watch(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { watcher.Close() })
+	return &watchTest{t, file, watcher}
+}
+
+// Writes b at off of the File
+func (w *watchTest) writeAt(b []byte, off int) {
+	w.t.Helper()
+	if _, err := w.file.WriteAt(b, int64(off)); err != nil {
+		w.t.Fatal(err)
+	}
+}
+
+// Polls the Watcher, checking the Updates
+func (w *watchTest) poll(want ...Update) []Update {
+	w.t.Helper()
+	updates, err := w.watcher.Poll()
+	if err != nil {
+		w.t.Fatal(err)
+	}
+	if len(updates) != len(want) || len(want) > 0 && !reflect.DeepEqual(updates, want) {
+		w.t.Fatalf("got updates %+v, want %+v", updates, want)
+	}
+	return updates
+}
+
+// Checks the values of a Channel in an Update
+func (w *watchTest) values(path string, update Update, want interface{}) {
+	w.t.Helper()
+	got, err := w.watcher.ReadUpdate(path, update)
+	if err != nil {
+		w.t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		w.t.Errorf("%s: got %v, want %v", path, got, want)
+	}
+	n, err := w.watcher.UpdateNumValues(path, update)
+	if err != nil {
+		w.t.Fatal(err)
+	}
+	wantN := uint64(0)
+	if want != nil {
+		wantN = uint64(reflect.ValueOf(want).Len())
+	}
+	if n != wantN {
+		w.t.Errorf("%s: got %d values, want %d", path, n, wantN)
+	}
+}
+
+func TestWatcherAppendedSegments(t *testing.T) {
+	a, b := ChannelPath("G", "A"), ChannelPath("G", "B")
+	unit, _ := NewProperty("unit", "V")
+	first := writeTestFile(t, []WriteObject{{a, []Property{unit}, []float64{1, 2}}, {b, nil, []int16{3}}})
+	second := writeTestFile(t, []WriteObject{{a, nil, []float64{4}}})
+	third := writeTestFile(t, []WriteObject{{b, nil, []int16{5, 6}}})
+
+	w := newWatchTest(t)
+	w.poll()
+
+	w.writeAt(first, 0)
+	updates := w.poll(Update{0, 0, 1})
+	w.values(a, updates[0], []float64{1, 2})
+	w.values(b, updates[0], []int16{3})
+	if got := w.watcher.Properties(a)["unit"].Value(); got != "V" {
+		t.Errorf("unit: got %v, want V", got)
+	}
+	w.poll()
+
+	// Two Segments written between Polls
+	w.writeAt(append(second, third...), len(first))
+	updates = w.poll(Update{1, 0, 1}, Update{2, 0, 1})
+	w.values(a, updates[0], []float64{4})
+	w.values(b, updates[0], nil)
+	w.values(b, updates[1], []int16{5, 6})
+	if n := len(w.watcher.Segments()); n != 3 {
+		t.Errorf("got %d segments, want 3", n)
+	}
+}
+
+func TestWatcherGrowingSegment(t *testing.T) {
+	a := ChannelPath("G", "A")
+	// A Segment of 3 Chunks of 2 values, still being written
+	segment := appendTestSegment(nil, KTocMetaData|KTocNewObjList|KTocRawData, func(s *segmentBuilder) {
+		s.u32(1)
+		s.str(a)
+		s.index(Int32, 2)
+		s.u32(0)
+	}, func(s *segmentBuilder) {
+		for i := uint32(0); i < 6; i++ {
+			s.u32(i)
+		}
+	})
+	length := append([]byte(nil), segment[12:20]...)
+	binary.LittleEndian.PutUint64(segment[12:20], incompleteSegLength)
+	dataPos := len(segment) - 24
+
+	w := newWatchTest(t)
+	// Metadata not yet completely written
+	w.writeAt(segment[:dataPos-2], 0)
+	w.poll()
+
+	// Metadata, and a Chunk and a half
+	w.writeAt(segment[dataPos-2:dataPos+12], dataPos-2)
+	updates := w.poll(Update{0, 0, 1})
+	w.values(a, updates[0], []int32{0, 1})
+
+	// Rest of the half Chunk
+	w.writeAt(segment[dataPos+12:dataPos+16], dataPos+12)
+	updates = w.poll(Update{0, 1, 1})
+	w.values(a, updates[0], []int32{2, 3})
+
+	// Final Chunk, then the Segment Length once the Segment is finished
+	w.writeAt(segment[dataPos+16:], dataPos+16)
+	w.writeAt(length, 12)
+	next := writeTestFile(t, []WriteObject{{a, nil, []int32{6}}})
+	w.writeAt(next, len(segment))
+	updates = w.poll(Update{0, 2, 1}, Update{1, 0, 1})
+	w.values(a, updates[0], []int32{4, 5})
+	w.values(a, updates[1], []int32{6})
+	w.poll()
+
+	if got := w.watcher.Segments()[0]; got.NumChunks != 3 || got.NextSegPos != uint64(len(segment)) {
+		t.Errorf("got %d chunks ending at %d, want 3 ending at %d", got.NumChunks, got.NextSegPos, len(segment))
+	}
+}
+
+func TestWatcherTruncated(t *testing.T) {
+	path := ChannelPath("G", "A")
+	segment := writeTestFile(t, []WriteObject{{path, nil, []float64{1, 2, 3}}})
+	binary.LittleEndian.PutUint64(segment[12:20], incompleteSegLength)
+
+	w := newWatchTest(t)
+	w.writeAt(segment, 0)
+	w.poll(Update{0, 0, 1})
+
+	// Chunks already reported can not be taken back
+	if err := w.file.Truncate(int64(len(segment) - 8)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.watcher.Poll(); !errors.Is(err, ErrTruncatedSegment) {
+		t.Errorf("got %v, want ErrTruncatedSegment", err)
+	}
+}
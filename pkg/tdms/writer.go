@@ -0,0 +1,400 @@
+package tdms
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// Writes TDMS Segments, such as to a new File
+//
+// Only what changed since the last Segment is written, the inverse of
+// ReadMetaData:
+// - Metadata is left out if nothing changed
+// - A new Object List is only written if the Objects with Raw Data change
+// - Unchanged Raw Data Indexes are written as matching the previous one
+// - Properties are only written if their value changed
+type Writer struct {
+	// Whether Raw Data is written Interleaved, one value of each Channel
+	// in turn, rather than each Channel's values one after the other
+	// Interleaved Channels must have the same number of values
+	Interleaved bool
+	// Whether Segments are written Big Endian, rather than Little Endian
+	BigEndian bool
+
+	w      io.Writer
+	closer io.Closer
+	// Bytes written so far, the Position of the next Segment
+	pos uint64
+	// Object Order of the last Segment
+	order []string
+	// Raw Data Index each Object was last written with, NoRawDataValue
+	// if it had no Raw Data, always in Little Endian
+	indexes map[string][]byte
+	// Data Type and value each Property was last written with, by Object
+	// Path and Property Name
+	properties map[string]map[string]string
+	buf        []byte
+	// Values of a single Object, while Interleaving
+	scratch []byte
+}
+
+// An Object written in a Segment
+type WriteObject struct {
+	// Object Path, see GroupPath and ChannelPath
+	Path string
+	// Properties set in this Segment, see NewProperty
+	Properties []Property
+	// Raw Data of a Channel, a slice of a type returned by
+	// ReadSegmentChannelData, []time.Time is written as Timestamps
+	// nil if the Object has no Raw Data in the Segment
+	Values interface{}
+}
+
+// Creates a Writer of Segments to w
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{
+		w:          w,
+		indexes:    make(map[string][]byte),
+		properties: make(map[string]map[string]string),
+	}
+}
+
+// Creates a Writer of Segments appended to the TDMS File in src
+// w must write to the end of src, such as the File opened for appending
+//
+// The Writer carries on from the last Segment, so Metadata that has not
+// changed is not written again
+// Files with problems found by Verify are not appended to, see Repair
+func NewAppendWriter(src Source, w io.Writer) (*Writer, error) {
+	writer := NewWriter(w)
+	writer.pos = uint64(src.Size())
+	if src.Size() == 0 {
+		return writer, nil
+	}
+	if issues := Verify(src); len(issues) > 0 {
+		return nil, issues[0].Err
+	}
+
+	segments, properties, err := ReadAllSegments(src)
+	if err != nil {
+		return nil, err
+	}
+	for _, segment := range segments {
+		for path, obj := range segment.Objects {
+			writer.indexes[path] = writtenIndex(obj)
+		}
+	}
+	writer.order = append(writer.order, segments[len(segments)-1].ObjectOrder...)
+
+	for path, propMap := range properties {
+		writer.properties[path] = make(map[string]string)
+		for name, property := range propMap {
+			written, err := appendProperty(nil, property, binary.LittleEndian)
+			if err != nil {
+				return nil, withPath(newError("read property", property.ValuePosition, err), path)
+			}
+			writer.properties[path][name] = string(written)
+		}
+	}
+	return writer, nil
+}
+
+// Creates the TDMS File at the given path to be written, replacing an
+// existing File
+//
+// The Writer must be Closed once finished with
+func Create(name string) (*Writer, error) {
+	file, err := os.Create(name)
+	if err != nil {
+		return nil, err
+	}
+	w := NewWriter(file)
+	w.closer = file
+	return w, nil
+}
+
+// Opens the TDMS File at the given path to append Segments to, see
+// NewAppendWriter
+// An existing .tdms_index File is stale once Segments are appended,
+// see WriteIndexFile
+//
+// The Writer must be Closed once finished with
+func OpenAppend(name string) (*Writer, error) {
+	file, err := os.OpenFile(name, os.O_RDWR|os.O_APPEND, 0)
+	if err != nil {
+		return nil, err
+	}
+	src, err := NewFileSource(file)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	w, err := NewAppendWriter(src, file)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	w.closer = file
+	return w, nil
+}
+
+// Closes the File written to, if opened by Create or OpenAppend
+func (w *Writer) Close() error {
+	if w.closer == nil {
+		return nil
+	}
+	return w.closer.Close()
+}
+
+// Creates a Property to write, its Data Type is set by the value's type,
+// see Property.Value
+// time.Time is written as a Timestamp, nil as Void
+func NewProperty(name string, value interface{}) (Property, error) {
+	dataType, ok := valueDataType(value)
+	if !ok {
+		return Property{}, fmt.Errorf("%w: property %s is a %T", ErrUnsupportedDataType, name, value)
+	}
+	if t, ok := value.(time.Time); ok {
+		value = LVTimestampFromTime(t)
+	}
+	return Property{
+		name,
+		dataType,
+		0,
+		formatValue(value),
+		nil,
+		value,
+	}, nil
+}
+
+// An Object of a Segment being written
+type writeObject struct {
+	path     string
+	dataType TdsDataType
+	values   interface{}
+	// Raw Data Index in Little Endian, NoRawDataValue if it has no Raw Data
+	index     []byte
+	numValues uint64
+	size      uint64
+	// Properties that changed, and how they are written
+	properties []Property
+	written    []string
+}
+
+// Writes a Segment holding the Objects, in order
+// The Raw Data of every Object is written as a single Chunk, Interleaved
+// and in the Byte Order set by the Writer
+//
+// The Objects with Raw Data must be the same, in the same order, as in
+// the last Segment for its Metadata to be reused
+// Nothing is written if nothing changed and there is no Raw Data
+func (w *Writer) WriteSegment(objects []WriteObject) error {
+	tocMask := uint32(0)
+	if w.Interleaved {
+		tocMask |= KTocInterleavedData
+	}
+	if w.BigEndian {
+		tocMask |= KTocBigEndian
+	}
+	order := byteOrder(tocMask)
+	segmentPos := int64(w.pos)
+
+	// Raw Data Indexes and changed Properties of each Object
+	objs := make([]writeObject, len(objects))
+	var dataPaths []string
+	var dataIndexes []int
+	dataLength := uint64(0)
+	seen := make(map[string]bool, len(objects))
+	for i, obj := range objects {
+		names, err := ParsePath(obj.Path)
+		if err == nil && len(names) != 2 && obj.Values != nil {
+			err = fmt.Errorf("%w: only channels have raw data", ErrInvalidPath)
+		}
+		if err != nil {
+			return &Error{"write segment", segmentPos, obj.Path, err}
+		}
+		if seen[obj.Path] {
+			return &Error{"write segment", segmentPos, obj.Path, ErrDuplicateObject}
+		}
+		seen[obj.Path] = true
+
+		dataType, ok := sliceDataType(obj.Values)
+		if !ok && obj.Values != nil {
+			err := fmt.Errorf("%w: %T", ErrUnsupportedDataType, obj.Values)
+			return &Error{"write segment", segmentPos, obj.Path, err}
+		}
+		numValues := arrayLength(obj.Values)
+		size := arraySize(dataType, obj.Values)
+		objs[i] = writeObject{obj.Path, dataType, obj.Values, NoRawDataValue, numValues, size, nil, nil}
+		if numValues > 0 {
+			if w.Interleaved && dataType.Size() == 0 {
+				err := fmt.Errorf("%w: 0x%X can not be interleaved", ErrUnsupportedDataType, dataType)
+				return &Error{"write segment", segmentPos, obj.Path, err}
+			}
+			if w.Interleaved && len(dataPaths) > 0 && numValues != objs[dataIndexes[0]].numValues {
+				err := fmt.Errorf("%w: interleaved objects have different numbers of values", ErrInvalidChunkSize)
+				return &Error{"write segment", segmentPos, obj.Path, err}
+			}
+			objs[i].index = appendRawDataIndex(nil, dataType, numValues, size, binary.LittleEndian)
+			dataLength += size
+			dataPaths = append(dataPaths, obj.Path)
+			dataIndexes = append(dataIndexes, i)
+		}
+
+		for _, property := range obj.Properties {
+			written, err := appendProperty(nil, property, binary.LittleEndian)
+			if err != nil {
+				return &Error{"write metadata", segmentPos, obj.Path, err}
+			}
+			if prev, present := w.properties[obj.Path][property.Name]; !present || prev != string(written) {
+				objs[i].properties = append(objs[i].properties, property)
+				objs[i].written = append(objs[i].written, string(written))
+			}
+		}
+	}
+
+	// Objects are only listed if they changed, unless the Objects with
+	// Raw Data change, which needs a new Object List
+	newObjList := !w.sameDataObjects(dataPaths)
+	inOrder := make(map[string]bool, len(w.order))
+	for _, path := range w.order {
+		inOrder[path] = true
+	}
+	var listed []writeObject
+	for _, obj := range objs {
+		if newObjList || !inOrder[obj.path] || len(obj.properties) > 0 || !bytes.Equal(obj.index, w.indexes[obj.path]) {
+			listed = append(listed, obj)
+		}
+	}
+	if len(listed) == 0 && dataLength == 0 {
+		return nil
+	}
+
+	// Lead In is filled in once the lengths are known
+	segment, _ := extend(w.buf[:0], leadInSize)
+	if len(listed) > 0 {
+		tocMask |= KTocMetaData
+		if newObjList {
+			tocMask |= KTocNewObjList
+		}
+
+		var d []byte
+		segment, d = extend(segment, 4)
+		order.PutUint32(d, uint32(len(listed)))
+		for _, obj := range listed {
+			rawDataIndex := obj.index
+			if !bytes.Equal(rawDataIndex, NoRawDataValue) {
+				if bytes.Equal(rawDataIndex, w.indexes[obj.path]) {
+					rawDataIndex = MatchesPreviousValue
+				} else {
+					rawDataIndex = appendRawDataIndex(nil, obj.dataType, obj.numValues, obj.size, order)
+				}
+			}
+			var err error
+			segment, err = appendObject(segment, obj.path, rawDataIndex, obj.properties, order)
+			if err != nil {
+				return &Error{"write metadata", segmentPos, obj.path, err}
+			}
+		}
+	}
+	metaLength := uint64(len(segment) - leadInSize)
+
+	if dataLength > 0 {
+		tocMask |= KTocRawData
+		if w.Interleaved {
+			segment = w.appendInterleaved(segment, objs, dataIndexes, order)
+		} else {
+			for _, i := range dataIndexes {
+				segment, _ = appendArray(segment, objs[i].dataType, objs[i].values, order)
+			}
+		}
+	}
+	appendLeadIn(segment[:0], tocMask, metaLength, dataLength)
+	w.buf = segment
+
+	_, err := w.w.Write(segment)
+	if err != nil {
+		return newError("write segment", segmentPos, err)
+	}
+	w.pos += uint64(len(segment))
+
+	// Kept as ReadMetaData would read them back
+	if newObjList {
+		w.order = w.order[:0]
+	}
+	for _, obj := range listed {
+		if newObjList || !inOrder[obj.path] {
+			w.order = append(w.order, obj.path)
+		}
+		w.indexes[obj.path] = obj.index
+		if len(obj.properties) > 0 && w.properties[obj.path] == nil {
+			w.properties[obj.path] = make(map[string]string)
+		}
+		for i, property := range obj.properties {
+			w.properties[obj.path][property.Name] = obj.written[i]
+		}
+	}
+	return nil
+}
+
+// Appends the Raw Data of the Objects at dataIndexes Interleaved, one
+// value of each in turn
+// Each Object's values are encoded together, then copied into place
+func (w *Writer) appendInterleaved(b []byte, objs []writeObject, dataIndexes []int, order binary.ByteOrder) []byte {
+	stride := 0
+	for _, i := range dataIndexes {
+		stride += objs[i].dataType.Size()
+	}
+	numValues := int(objs[dataIndexes[0]].numValues)
+	start := len(b)
+	b, _ = extend(b, stride*numValues)
+
+	offset := 0
+	for _, i := range dataIndexes {
+		width := objs[i].dataType.Size()
+		w.scratch, _ = appendArray(w.scratch[:0], objs[i].dataType, objs[i].values, order)
+		for j := 0; j < numValues; j++ {
+			pos := start + j*stride + offset
+			copy(b[pos:pos+width], w.scratch[j*width:(j+1)*width])
+		}
+		offset += width
+	}
+	return b
+}
+
+// Whether the Objects with Raw Data are those of the last Segment,
+// in the same order
+func (w *Writer) sameDataObjects(dataPaths []string) bool {
+	if len(w.order) == 0 {
+		return false
+	}
+	i := 0
+	for _, path := range w.order {
+		if bytes.Equal(w.indexes[path], NoRawDataValue) {
+			continue
+		}
+		if i >= len(dataPaths) || dataPaths[i] != path {
+			return false
+		}
+		i++
+	}
+	return i == len(dataPaths)
+}
+
+// Raw Data Index of a Segment Object read from a File, as the Writer
+// compares it, see Writer.indexes
+// DAQmx Raw Data Indexes are never matched, so their header is kept
+func writtenIndex(obj SegmentObject) []byte {
+	switch {
+	case bytes.Equal(obj.RawDataIndexHeader, NoRawDataValue):
+		return NoRawDataValue
+	case obj.RawDataIndex.DAQmx != nil:
+		return obj.RawDataIndexHeader
+	}
+	index := obj.RawDataIndex
+	return appendRawDataIndex(nil, index.DataType, index.NumValues, index.RawDataSize, binary.LittleEndian)
+}
@@ -0,0 +1,490 @@
+package tdms
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"math/big"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// Writes Segments of Objects with a Writer, returning the File's bytes
+func writeTestFile(t testing.TB, segments ...[]WriteObject) []byte {
+	t.Helper()
+	return writeTestFileMode(t, false, false, segments...)
+}
+
+// Writes a File with the given layout and Byte Order, one Segment per
+// list of Objects
+func writeTestFileMode(t testing.TB, interleaved bool, bigEndian bool, segments ...[]WriteObject) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	w.Interleaved = interleaved
+	w.BigEndian = bigEndian
+	for _, objects := range segments {
+		err := w.WriteSegment(objects)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return buf.Bytes()
+}
+
+// Reads a written File back, failing the test if it can not be read
+func readTestFile(t *testing.T, b []byte) *File {
+	t.Helper()
+	if issues := Verify(NewBytesSource(b)); len(issues) > 0 {
+		t.Fatalf("written file has issues: %v", issues)
+	}
+	f, err := NewFile(NewBytesSource(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+// Values of a Channel, failing the test if they can not be read
+func channelData(t *testing.T, f *File, group string, channel string) interface{} {
+	t.Helper()
+	g, err := f.Group(group)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := g.Channel(channel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := c.Data()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+var testTime = time.Date(2024, 1, 2, 3, 4, 5, 123456789, time.UTC)
+
+func TestWriterRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		values interface{}
+		// Values read back, if not the values written
+		want interface{}
+	}{
+		{"Int8", []int8{math.MinInt8, -1, 0, math.MaxInt8}, nil},
+		{"Int16", []int16{math.MinInt16, -1, 0, math.MaxInt16}, nil},
+		{"Int32", []int32{math.MinInt32, -1, 0, math.MaxInt32}, nil},
+		{"Int64", []int64{math.MinInt64, -1, 0, math.MaxInt64}, nil},
+		{"Uint8", []uint8{0, 1, math.MaxUint8}, nil},
+		{"Uint16", []uint16{0, 1, math.MaxUint16}, nil},
+		{"Uint32", []uint32{0, 1, math.MaxUint32}, nil},
+		{"Uint64", []uint64{0, 1, math.MaxUint64}, nil},
+		{"SGL", []float32{-1.5, 0, float32(math.Inf(1)), math.MaxFloat32}, nil},
+		{"DBL", []float64{-2.25, 0, math.Inf(-1), math.SmallestNonzeroFloat64}, nil},
+		{"Boolean", []bool{true, false, false, true}, nil},
+		{"ComplexSGL", []complex64{complex(1, -2), 0}, nil},
+		{"ComplexDBL", []complex128{complex(3, 4), complex(-1, math.MaxFloat64)}, nil},
+		{"EXT", []*big.Float{big.NewFloat(1.5), big.NewFloat(-3), big.NewFloat(math.Inf(1)), nil}, nil},
+		{"String", []string{"a", "", "héllo", "with 'quotes'"}, nil},
+		{"Timestamp", []LVTimestamp{{-1, 0}, {3786825600, 1 << 63}}, nil},
+		{"Time", []time.Time{testTime}, []LVTimestamp{LVTimestampFromTime(testTime)}},
+	}
+	modes := []struct {
+		name        string
+		interleaved bool
+		bigEndian   bool
+	}{
+		{"Contiguous", false, false},
+		{"Interleaved", true, false},
+		{"Big Endian", false, true},
+		{"Interleaved Big Endian", true, true},
+	}
+	for _, mode := range modes {
+		for _, test := range tests {
+			t.Run(mode.name+"/"+test.name, func(t *testing.T) {
+				want := test.want
+				if want == nil {
+					want = test.values
+				}
+				// Written after a Channel of single Bytes, so Interleaved
+				// values are not aligned
+				path := ChannelPath("Group", test.name)
+				other := make([]uint8, reflect.ValueOf(test.values).Len())
+				for i := range other {
+					other[i] = uint8(i)
+				}
+				objects := []WriteObject{{ChannelPath("Group", "Other"), nil, other}, {path, nil, test.values}}
+
+				// Strings vary in size, so can not be Interleaved
+				if mode.interleaved && test.name == "String" {
+					w := NewWriter(&bytes.Buffer{})
+					w.Interleaved = true
+					if err := w.WriteSegment(objects); !errors.Is(err, ErrUnsupportedDataType) {
+						t.Errorf("got %v, want ErrUnsupportedDataType", err)
+					}
+					return
+				}
+
+				// Three Segments, so values are read across Segments
+				f := readTestFile(t, writeTestFileMode(t, mode.interleaved, mode.bigEndian, objects, objects, objects))
+				data := channelData(t, f, "Group", test.name)
+				wantValue := reflect.ValueOf(want)
+				all := reflect.MakeSlice(wantValue.Type(), 0, 3*wantValue.Len())
+				for i := 0; i < 3; i++ {
+					all = reflect.AppendSlice(all, wantValue)
+				}
+				if !equalValues(data, all.Interface()) {
+					t.Errorf("got %v, want %v", data, all.Interface())
+				}
+				var wantOther []uint8
+				for i := 0; i < 3; i++ {
+					wantOther = append(wantOther, other...)
+				}
+				if got := channelData(t, f, "Group", "Other"); !reflect.DeepEqual(got, wantOther) {
+					t.Errorf("other: got %v, want %v", got, wantOther)
+				}
+			})
+		}
+	}
+}
+
+// Whether values read equal those written, comparing EXT values by value
+func equalValues(got interface{}, want interface{}) bool {
+	wantEXT, ok := want.([]*big.Float)
+	if !ok {
+		return reflect.DeepEqual(got, want)
+	}
+	gotEXT, ok := got.([]*big.Float)
+	if !ok || len(gotEXT) != len(wantEXT) {
+		return false
+	}
+	for i := range wantEXT {
+		if !equalEXT(gotEXT[i], wantEXT[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Whether two EXT values are equal, nil for NaN
+func equalEXT(got *big.Float, want *big.Float) bool {
+	if got == nil || want == nil {
+		return got == want
+	}
+	return got.Cmp(want) == 0
+}
+
+func TestWriterProperties(t *testing.T) {
+	// time.Time is written as a Timestamp
+	values := []interface{}{
+		int8(-1), int16(-2), int32(-3), int64(-4),
+		uint8(5), uint16(6), uint32(7), uint64(8),
+		float32(1.5), 2.5, big.NewFloat(3.25), true,
+		complex64(1 + 2i), complex128(3 + 4i),
+		LVTimestamp{1, 2}, testTime, "text", nil,
+	}
+	var properties []Property
+	for i, value := range values {
+		property, err := NewProperty(string(rune('a'+i)), value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		properties = append(properties, property)
+	}
+	override, err := NewProperty("a", "override")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	group := GroupPath("Group")
+	f := readTestFile(t, writeTestFile(t,
+		[]WriteObject{{rootPath, properties, nil}, {group, properties, nil}},
+		[]WriteObject{{group, []Property{override}, nil}},
+	))
+
+	for _, got := range []map[string]Property{f.Properties(), f.groups[0].Properties()} {
+		// The first Property is overridden below
+		for _, property := range properties[1:] {
+			p, present := got[property.Name]
+			if !present {
+				t.Fatalf("property %s is missing", property.Name)
+			}
+			if p.DataType != property.DataType || p.StringValue != property.StringValue {
+				t.Errorf("property %s: got %s (0x%X), want %s (0x%X)", property.Name, p.StringValue, p.DataType, property.StringValue, property.DataType)
+			}
+			want, ok := property.Value().(*big.Float)
+			if ok && !equalEXT(p.Value().(*big.Float), want) || !ok && !reflect.DeepEqual(p.Value(), property.Value()) {
+				t.Errorf("property %s: got %v, want %v", property.Name, p.Value(), property.Value())
+			}
+		}
+	}
+
+	// Only the Group's Property was overridden
+	if got := f.groups[0].Properties()["a"].Value(); got != "override" {
+		t.Errorf("group property a: got %v, want override", got)
+	}
+	if got := f.Properties()["a"].Value(); got != int8(-1) {
+		t.Errorf("file property a: got %v, want -1", got)
+	}
+}
+
+func TestWriterDroppedData(t *testing.T) {
+	a, b := ChannelPath("G", "A"), ChannelPath("G", "B")
+	unit, _ := NewProperty("unit", "V")
+	f := readTestFile(t, writeTestFile(t,
+		[]WriteObject{{a, nil, []float64{1, 2}}, {b, nil, []float64{3, 4}}},
+		// B only has a Property set, without values
+		[]WriteObject{{a, nil, []float64{5}}, {b, []Property{unit}, nil}},
+		[]WriteObject{{a, nil, []float64{6}}, {b, nil, []float64{7}}},
+	))
+
+	if got, want := channelData(t, f, "G", "A"), []float64{1, 2, 5, 6}; !reflect.DeepEqual(got, want) {
+		t.Errorf("A: got %v, want %v", got, want)
+	}
+	if got, want := channelData(t, f, "G", "B"), []float64{3, 4, 7}; !reflect.DeepEqual(got, want) {
+		t.Errorf("B: got %v, want %v", got, want)
+	}
+}
+
+func TestWriterErrors(t *testing.T) {
+	channel := ChannelPath("Group", "Channel")
+	mistyped, _ := NewProperty("mistyped", int8(1))
+	mistyped.DataType = String
+
+	tests := []struct {
+		name    string
+		objects []WriteObject
+		want    error
+	}{
+		{"Invalid Path", []WriteObject{{"Group", nil, nil}}, ErrInvalidPath},
+		{"Group Values", []WriteObject{{GroupPath("Group"), nil, []float64{1}}}, ErrInvalidPath},
+		{"Root Values", []WriteObject{{rootPath, nil, []float64{1}}}, ErrInvalidPath},
+		{"Duplicate", []WriteObject{{channel, nil, nil}, {channel, nil, nil}}, ErrDuplicateObject},
+		{"Unsupported Values", []WriteObject{{channel, nil, []int{1}}}, ErrUnsupportedDataType},
+		{"Mistyped Property", []WriteObject{{channel, []Property{mistyped}, nil}}, ErrUnsupportedDataType},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			w := NewWriter(nil)
+			err := w.WriteSegment(test.objects)
+			if !errors.Is(err, test.want) {
+				t.Errorf("got %v, want %v", err, test.want)
+			}
+		})
+	}
+
+	if _, err := NewProperty("slice", []int{1}); !errors.Is(err, ErrUnsupportedDataType) {
+		t.Errorf("got %v, want ErrUnsupportedDataType", err)
+	}
+}
+
+func TestWriterReuse(t *testing.T) {
+	a, b := ChannelPath("G", "A"), ChannelPath("G", "B")
+	unit, _ := NewProperty("unit", "V")
+	otherUnit, _ := NewProperty("unit", "A")
+	const newSegment = KTocMetaData | KTocNewObjList | KTocRawData
+
+	tests := []struct {
+		name     string
+		segments [][]WriteObject
+		// ToC Mask of each Segment written
+		toc    []uint32
+		values map[string]interface{}
+	}{
+		{
+			"Unchanged",
+			[][]WriteObject{
+				{{a, []Property{unit}, []float64{1, 2}}},
+				{{a, []Property{unit}, []float64{3, 4}}},
+			},
+			[]uint32{newSegment, KTocRawData},
+			map[string]interface{}{a: []float64{1, 2, 3, 4}},
+		},
+		{
+			"Changed Property",
+			[][]WriteObject{
+				{{a, []Property{unit}, []float64{1, 2}}},
+				{{a, []Property{otherUnit}, []float64{3, 4}}},
+			},
+			[]uint32{newSegment, KTocMetaData | KTocRawData},
+			map[string]interface{}{a: []float64{1, 2, 3, 4}},
+		},
+		{
+			"Changed Number of Values",
+			[][]WriteObject{
+				{{a, nil, []float64{1, 2}}},
+				{{a, nil, []float64{3}}},
+			},
+			[]uint32{newSegment, KTocMetaData | KTocRawData},
+			map[string]interface{}{a: []float64{1, 2, 3}},
+		},
+		{
+			"No Values",
+			[][]WriteObject{
+				{{a, nil, []float64{1, 2}}},
+				{{a, []Property{unit}, nil}},
+				{{a, nil, []float64{3}}},
+			},
+			[]uint32{newSegment, KTocMetaData | KTocNewObjList, newSegment},
+			map[string]interface{}{a: []float64{1, 2, 3}},
+		},
+		{
+			"Channel Dropped",
+			[][]WriteObject{
+				{{a, nil, []float64{1}}, {b, nil, []int32{2}}},
+				{{a, nil, []float64{3}}},
+				{{a, nil, []float64{4}}},
+			},
+			[]uint32{newSegment, newSegment, KTocRawData},
+			map[string]interface{}{a: []float64{1, 3, 4}, b: []int32{2}},
+		},
+		{
+			"Channel Dropped and Re-added",
+			[][]WriteObject{
+				{{a, nil, []float64{1}}, {b, nil, []int32{2}}},
+				{{a, nil, []float64{3}}},
+				{{a, nil, []float64{4}}, {b, nil, []int32{5}}},
+				{{a, nil, []float64{6}}, {b, nil, []int32{7}}},
+			},
+			[]uint32{newSegment, newSegment, newSegment, KTocRawData},
+			map[string]interface{}{a: []float64{1, 3, 4, 6}, b: []int32{2, 5, 7}},
+		},
+		{
+			// B is listed again without its Raw Data, after the Object List
+			// it had Raw Data in
+			"Channel Dropped and Re-listed Without Values",
+			[][]WriteObject{
+				{{a, nil, []float64{1}}, {b, nil, []int32{2}}},
+				{{a, nil, []float64{3}}},
+				{{a, nil, []float64{4}}, {b, []Property{unit}, nil}},
+				{{a, nil, []float64{5}}, {b, nil, []int32{6}}},
+			},
+			[]uint32{newSegment, newSegment, KTocMetaData | KTocRawData, newSegment},
+			map[string]interface{}{a: []float64{1, 3, 4, 5}, b: []int32{2, 6}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			f := readTestFile(t, writeTestFile(t, test.segments...))
+
+			var toc []uint32
+			for _, segment := range f.Segments() {
+				toc = append(toc, segment.KToCMask)
+			}
+			if !reflect.DeepEqual(toc, test.toc) {
+				t.Errorf("got ToC masks %#v, want %#v", toc, test.toc)
+			}
+			for path, want := range test.values {
+				names, _ := ParsePath(path)
+				if got := channelData(t, f, names[0], names[1]); !reflect.DeepEqual(got, want) {
+					t.Errorf("%s: got %v, want %v", path, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestWriterMatchesPrevious(t *testing.T) {
+	path := ChannelPath("G", "A")
+	unit, _ := NewProperty("unit", "V")
+	otherUnit, _ := NewProperty("unit", "A")
+	b := writeTestFile(t,
+		[]WriteObject{{path, nil, []float64{1, 2}}},
+		[]WriteObject{{path, []Property{unit}, []float64{3, 4}}},
+		[]WriteObject{{path, []Property{otherUnit}, []float64{5}}},
+	)
+	f := readTestFile(t, b)
+
+	// Raw Data Index of the only Object listed, after the Number of Objects
+	// and its Path
+	indexHeader := func(segment Segment) []byte {
+		pos := segment.Position + leadInSize + 4 + 4 + uint64(len(path))
+		return b[pos : pos+4]
+	}
+	segments := f.Segments()
+	if got := indexHeader(segments[1]); !bytes.Equal(got, MatchesPreviousValue) {
+		t.Errorf("unchanged index: got header %X, want %X", got, MatchesPreviousValue)
+	}
+	if got := indexHeader(segments[2]); bytes.Equal(got, MatchesPreviousValue) || bytes.Equal(got, NoRawDataValue) {
+		t.Errorf("changed index: got header %X, want a raw data index", got)
+	}
+	if got, want := channelData(t, f, "G", "A"), []float64{1, 2, 3, 4, 5}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestOpenAppend(t *testing.T) {
+	demo, err := os.ReadFile("../../testFiles/demo.tdms")
+	if err != nil {
+		t.Fatal(err)
+	}
+	name := filepath.Join(t.TempDir(), "demo.tdms")
+	if err := os.WriteFile(name, demo, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	before := readTestFile(t, demo)
+
+	// The Channels of the last Segment, with the same numbers of values
+	last := before.Segments()[len(before.Segments())-1]
+	var objects []WriteObject
+	for _, path := range last.ObjectOrder {
+		obj := last.Objects[path]
+		if !obj.HasRawData() {
+			continue
+		}
+		values, err := ReadSegmentChannelData(NewBytesSource(demo), last, path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		objects = append(objects, WriteObject{path, nil, values})
+	}
+
+	w, err := OpenAppend(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteSegment(objects); err != nil {
+		t.Fatal(err)
+	}
+	added := ChannelPath("Square Wave", "Added")
+	if err := w.WriteSegment(append(objects, WriteObject{added, nil, []uint8{1, 2, 3}})); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(b[:len(demo)], demo) {
+		t.Fatal("appending changed the existing segments")
+	}
+	after := readTestFile(t, b)
+	segments := after.Segments()[len(before.Segments()):]
+	if len(segments) != 2 {
+		t.Fatalf("got %d appended segments, want 2", len(segments))
+	}
+	// The Metadata of the last Segment is reused
+	if segments[0].KToCMask != KTocRawData {
+		t.Errorf("got ToC mask %#x, want %#x", segments[0].KToCMask, KTocRawData)
+	}
+
+	for _, obj := range objects {
+		names, _ := ParsePath(obj.Path)
+		before := reflect.ValueOf(channelData(t, before, names[0], names[1]))
+		want := reflect.AppendSlice(before, reflect.ValueOf(obj.Values))
+		want = reflect.AppendSlice(want, reflect.ValueOf(obj.Values))
+		if got := channelData(t, after, names[0], names[1]); !reflect.DeepEqual(got, want.Interface()) {
+			t.Errorf("%s: got %d values, want %d", obj.Path, reflect.ValueOf(got).Len(), want.Len())
+		}
+	}
+	if got, want := channelData(t, after, "Square Wave", "Added"), []uint8{1, 2, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("added: got %v, want %v", got, want)
+	}
+}